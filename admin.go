@@ -0,0 +1,171 @@
+package main
+
+import (
+	"crypto/subtle"
+	"sort"
+
+	"github.com/zerozwt/brelay/client"
+	"github.com/zerozwt/toyframe"
+)
+
+// admin handlers are disabled if gConf.AdminKey is empty
+
+func checkAdminKey(key string) bool {
+	return len(gConf.AdminKey) > 0 && subtle.ConstantTimeCompare([]byte(gConf.AdminKey), []byte(key)) == 1
+}
+
+func adminListSubscribersHandler(ctx *toyframe.Context) error {
+	wgAll.Add(1)
+	ctx.AddCloseHandler(wgAll.Done)
+	ctx.SetInterruptor(gServer.CloseChannel())
+
+	req := client.MsgAdminListSubscribersReq{}
+	if err := ctx.ReadObj(&req); err != nil {
+		logger().Printf("read admin_list_subscribers request failed: %v", err)
+		return err
+	}
+
+	rsp := client.MsgAdminListSubscribersRsp{Ok: true}
+	if !checkAdminKey(req.Key) {
+		logger().Printf("admin_list_subscribers from %s failed: admin key check failed", ctx.RemoteAddr())
+		rsp.Ok = false
+		rsp.Msg = "admin key check failed"
+	}
+
+	if rsp.Ok {
+		subscribers, err := gDanmaku.AdminSubscribers()
+		if err != nil {
+			return err // it has to be a ErrInterrupted
+		}
+		rsp.Subscribers = subscribers
+	}
+
+	if err := ctx.WriteObj(&rsp); err != nil {
+		logger().Printf("send admin_list_subscribers reponse failed: %v", err)
+	}
+	return nil
+}
+
+func adminListRoomsHandler(ctx *toyframe.Context) error {
+	wgAll.Add(1)
+	ctx.AddCloseHandler(wgAll.Done)
+	ctx.SetInterruptor(gServer.CloseChannel())
+
+	req := client.MsgAdminListRoomsReq{}
+	if err := ctx.ReadObj(&req); err != nil {
+		logger().Printf("read admin_list_rooms request failed: %v", err)
+		return err
+	}
+
+	rsp := client.MsgAdminListRoomsRsp{Ok: true}
+	if !checkAdminKey(req.Key) {
+		logger().Printf("admin_list_rooms from %s failed: admin key check failed", ctx.RemoteAddr())
+		rsp.Ok = false
+		rsp.Msg = "admin key check failed"
+	}
+
+	if rsp.Ok {
+		counts, err := gDanmaku.RoomSubscriberCounts()
+		if err != nil {
+			return err // it has to be a ErrInterrupted
+		}
+		rooms := gClientMgr.AdminRooms()
+		for idx := range rooms {
+			rooms[idx].Subscribers = counts[rooms[idx].RoomID]
+		}
+		sort.Slice(rooms, func(i, j int) bool { return rooms[i].RoomID < rooms[j].RoomID })
+		rsp.Rooms = rooms
+	}
+
+	if err := ctx.WriteObj(&rsp); err != nil {
+		logger().Printf("send admin_list_rooms reponse failed: %v", err)
+	}
+	return nil
+}
+
+func adminKickHandler(ctx *toyframe.Context) error {
+	wgAll.Add(1)
+	ctx.AddCloseHandler(wgAll.Done)
+	ctx.SetInterruptor(gServer.CloseChannel())
+
+	req := client.MsgAdminKickReq{}
+	if err := ctx.ReadObj(&req); err != nil {
+		logger().Printf("read admin_kick request failed: %v", err)
+		return err
+	}
+
+	rsp := client.MsgAdminRsp{Ok: true}
+	if !checkAdminKey(req.Key) {
+		logger().Printf("admin_kick from %s failed: admin key check failed", ctx.RemoteAddr())
+		rsp.Ok = false
+		rsp.Msg = "admin key check failed"
+	}
+
+	if rsp.Ok {
+		found, internal, err := gDanmaku.Kick(req.SubscriberID)
+		if err != nil {
+			return err // it has to be a ErrInterrupted
+		}
+		switch {
+		case !found:
+			rsp.Ok = false
+			rsp.Msg = "subscriber not found"
+		case internal:
+			rsp.Ok = false
+			rsp.Msg = "subscriber of sink could not be kicked"
+		default:
+			logger().Printf("subscriber %d kicked by admin from %s", req.SubscriberID, ctx.RemoteAddr())
+		}
+	}
+
+	if err := ctx.WriteObj(&rsp); err != nil {
+		logger().Printf("send admin_kick reponse failed: %v", err)
+	}
+	return nil
+}
+
+func adminRoomHandler(ctx *toyframe.Context) error {
+	wgAll.Add(1)
+	ctx.AddCloseHandler(wgAll.Done)
+	ctx.SetInterruptor(gServer.CloseChannel())
+
+	req := client.MsgAdminRoomReq{}
+	if err := ctx.ReadObj(&req); err != nil {
+		logger().Printf("read admin_room request failed: %v", err)
+		return err
+	}
+
+	rsp := client.MsgAdminRsp{Ok: true}
+	if !checkAdminKey(req.Key) {
+		logger().Printf("admin_room from %s failed: admin key check failed", ctx.RemoteAddr())
+		rsp.Ok = false
+		rsp.Msg = "admin key check failed"
+	}
+
+	if rsp.Ok {
+		switch req.Action {
+		case client.ADMIN_ROOM_RECONNECT:
+			if !gClientMgr.ReconnectRoom(req.RoomID) {
+				rsp.Ok = false
+				rsp.Msg = "room not connected"
+			}
+		case client.ADMIN_ROOM_CLOSE:
+			dropped, err := gDanmaku.DropRoom(req.RoomID)
+			if err != nil {
+				return err // it has to be a ErrInterrupted
+			}
+			if !gClientMgr.CloseRoom(req.RoomID) && !dropped {
+				rsp.Ok = false
+				rsp.Msg = "room not connected"
+			}
+		default:
+			rsp.Ok = false
+			rsp.Msg = "unknown action"
+		}
+	}
+
+	if err := ctx.WriteObj(&rsp); err != nil {
+		logger().Printf("send admin_room reponse failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/tinylib/msgp/msgp"
+	"github.com/zerozwt/toyframe"
+	"github.com/zerozwt/toyframe/dialer"
+)
+
+// AdminClient calls admin handlers of a relay server with its admin key
+type AdminClient struct {
+	key string
+
+	network string
+	address string
+	dial    dialer.DialFunc
+
+	ich chan struct{}
+}
+
+func NewAdminClient(key, network, address string, dial dialer.DialFunc, ich chan struct{}) *AdminClient {
+	return &AdminClient{
+		key:     key,
+		network: network,
+		address: address,
+		dial:    dial,
+		ich:     ich,
+	}
+}
+
+// ListSubscribers returns all subscribers on the server
+func (c *AdminClient) ListSubscribers() ([]MsgAdminSubscriber, error) {
+	rsp := MsgAdminListSubscribersRsp{}
+	if err := c.call("admin_list_subscribers", &MsgAdminListSubscribersReq{Key: c.key}, &rsp); err != nil {
+		return nil, err
+	}
+	if len(rsp.Msg) > 0 {
+		return nil, errors.New(rsp.Msg)
+	}
+	return rsp.Subscribers, nil
+}
+
+// ListRooms returns all live rooms with upstream connections on the server
+func (c *AdminClient) ListRooms() ([]MsgAdminRoom, error) {
+	rsp := MsgAdminListRoomsRsp{}
+	if err := c.call("admin_list_rooms", &MsgAdminListRoomsReq{Key: c.key}, &rsp); err != nil {
+		return nil, err
+	}
+	if len(rsp.Msg) > 0 {
+		return nil, errors.New(rsp.Msg)
+	}
+	return rsp.Rooms, nil
+}
+
+// Kick logs out a subscriber, its login stream is closed after remaining msgs are sent
+func (c *AdminClient) Kick(sub_id uint32) error {
+	return c.callAdmin("admin_kick", &MsgAdminKickReq{Key: c.key, SubscriberID: sub_id})
+}
+
+// ReconnectRoom reconnects the upstream connection of a room
+func (c *AdminClient) ReconnectRoom(room_id int) error {
+	return c.callAdmin("admin_room", &MsgAdminRoomReq{Key: c.key, RoomID: room_id, Action: ADMIN_ROOM_RECONNECT})
+}
+
+// CloseRoom closes the upstream connection of a room and drops all subscriptions of it
+func (c *AdminClient) CloseRoom(room_id int) error {
+	return c.callAdmin("admin_room", &MsgAdminRoomReq{Key: c.key, RoomID: room_id, Action: ADMIN_ROOM_CLOSE})
+}
+
+func (c *AdminClient) callAdmin(method string, req msgp.MarshalSizer) error {
+	rsp := MsgAdminRsp{}
+	if err := c.call(method, req, &rsp); err != nil {
+		return err
+	}
+	if len(rsp.Msg) > 0 {
+		return errors.New(rsp.Msg)
+	}
+	return nil
+}
+
+func (c *AdminClient) call(method string, req msgp.MarshalSizer, rsp msgp.Unmarshaler) error {
+	ctx, err := toyframe.CallWithInterruptor(c.network, c.address, method, c.dial, c.ich, req)
+	if err != nil {
+		return err
+	}
+	defer ctx.Close()
+	return ctx.ReadObj(rsp)
+}
@@ -1,66 +1,171 @@
 package client
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/tinylib/msgp/msgp"
 	"github.com/zerozwt/toyframe"
 	"github.com/zerozwt/toyframe/dialer"
 )
 
 type Client struct {
+	last_seq uint64 // accessed atomically, keep 64-bit aligned
+
 	id   uint32
 	name string
 	sec  []byte
+	ack  bool
+
+	overflow string
+	flush    time.Duration
 
-	network string
-	address string
-	dial    dialer.DialFunc
+	endpoints *endpointSet
+	dial      dialer.DialFunc
 
 	ich chan struct{}
+
+	handlers handlerRegistry // handlers of msgs dispatched by Run
 }
 
 func NewBRelayClient(name, network, address string, dial dialer.DialFunc, ich chan struct{}) *Client {
-	return &Client{
-		name:    name,
-		network: network,
-		address: address,
-		dial:    dial,
-		ich:     ich,
+	ret, _ := NewBRelayClientWithEndpoints(name, []Endpoint{{Network: network, Address: address}}, dial, ich)
+	return ret
+}
+
+// NewBRelayClientWithEndpoints creates a client failing over among relay servers. Login tries
+// endpoints in the order of failover policy until succeeded, subscriptions made through the
+// client are re-applied to the new subscriber on every Login after the first one, whichever
+// endpoint it lands on. With a single endpoint they are never re-applied. Other calls go to
+// the endpoint of the last Login. ErrNoEndpoint is returned if endpoints is empty.
+func NewBRelayClientWithEndpoints(name string, endpoints []Endpoint, dial dialer.DialFunc, ich chan struct{}) (*Client, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoint
 	}
+	return &Client{
+		name:      name,
+		endpoints: newEndpointSet(endpoints),
+		dial:      dial,
+		ich:       ich,
+	}, nil
+}
+
+// SeqGapError is returned by ReadMessagesChecked along with msgs of the current batch when
+// batches between Last and Current were lost, the context is still usable.
+type SeqGapError struct {
+	Last    uint64
+	Current uint64
+}
+
+func (e *SeqGapError) Error() string {
+	return fmt.Sprintf("batch seq gap detected: last %d, current %d", e.Last, e.Current)
+}
+
+// SetAckMode makes the server retain sent batches until acked by Ack, so that they could be
+// redelivered by Resume. It takes effect on next Login.
+func (c *Client) SetAckMode(enable bool) {
+	c.ack = enable
+}
+
+// SetOverflowPolicy requests one of OVERFLOW_* policies applied when the client could not
+// keep up with msgs. It takes effect on next Login.
+func (c *Client) SetOverflowPolicy(policy string) {
+	c.overflow = policy
+}
+
+// SetFlushInterval requests the interval of receiving batches, 0 for server default. It
+// takes effect on next Login.
+func (c *Client) SetFlushInterval(interval time.Duration) {
+	c.flush = interval
 }
 
 func (c *Client) Login() (*toyframe.Context, error) {
-	ctx, err := toyframe.CallWithInterruptor(c.network, c.address, "login", c.dial, c.ich, &MsgLoginReq{ID: c.name})
-	if err != nil {
-		return nil, err
+	return c.LoginContext(context.Background())
+}
+
+// LoginContext is Login which gives up once ctx is done, the returned login stream is not
+// bound to ctx.
+func (c *Client) LoginContext(ctx context.Context) (*toyframe.Context, error) {
+	err := ErrNoEndpoint
+	for _, idx := range c.endpoints.loginOrder() {
+		rsp := MsgLoginRsp{}
+		var stream *toyframe.Context
+		stream, err = c.callEndpoint(ctx, idx, "login",
+			&MsgLoginReq{ID: c.name, Ack: c.ack, Overflow: c.overflow, FlushInterval: uint32(c.flush / time.Millisecond)}, &rsp)
+		if err != nil {
+			if ctx.Err() != nil || err == toyframe.ErrInterrupted {
+				return nil, err
+			}
+			continue
+		}
+
+		// the last subscriber is kept until subscriptions are re-applied to the new one
+		if subs := c.endpoints.resubscriptions(); len(subs) > 0 {
+			if err = c.subscribe(ctx, idx, "subscribe", rsp.SubscriberID, rsp.SubscriberSecret, subs); err != nil {
+				c.logout(ctx, idx, rsp.SubscriberID, rsp.SubscriberSecret)
+				stream.Close()
+				return nil, err
+			}
+		}
+
+		c.id = rsp.SubscriberID
+		c.sec = rsp.SubscriberSecret
+		atomic.StoreUint64(&(c.last_seq), 0)
+		c.endpoints.setCurrent(idx)
+		return stream, nil
 	}
+	return nil, err
+}
 
-	rsp := MsgLoginRsp{}
-	err = ctx.ReadObj(&rsp)
+// Resume re-attaches to the subscriber created by last successful Login after the login
+// stream is broken. Msgs cached by the server while detached are delivered through the
+// returned context, as well as subscriptions are kept.
+func (c *Client) Resume() (*toyframe.Context, error) {
+	return c.ResumeContext(context.Background())
+}
+
+// ResumeContext is Resume which gives up once ctx is done.
+func (c *Client) ResumeContext(ctx context.Context) (*toyframe.Context, error) {
+	rsp := MsgResumeRsp{}
+	stream, err := c.call(ctx, "resume",
+		&MsgResumeReq{ID: c.name, SubscriberID: c.id, SubscriberSecret: c.sec, LastSeq: c.LastSeq()}, &rsp)
 	if err != nil {
-		ctx.Close()
 		return nil, err
 	}
 
-	c.id = rsp.SubscriberID
-	c.sec = rsp.SubscriberSecret
-	return ctx, nil
+	if len(rsp.Msg) > 0 {
+		stream.Close()
+		return nil, errors.New(rsp.Msg)
+	}
+	return stream, nil
 }
 
 func (c *Client) Logout() error {
-	ctx, err := toyframe.CallWithInterruptor(c.network, c.address, "logout", c.dial, c.ich,
-		&MsgLogoutReq{SubscriberID: c.id, SubscriberSecret: c.sec})
-	if err != nil {
+	return c.LogoutContext(context.Background())
+}
+
+// LogoutContext is Logout which gives up once ctx is done.
+func (c *Client) LogoutContext(ctx context.Context) error {
+	if err := c.logout(ctx, -1, c.id, c.sec); err != nil {
 		return err
 	}
-	defer ctx.Close()
+	c.endpoints.setSubscriptions(nil)
+	return nil
+}
 
+// logout logs out subscriber id on the endpoint of idx
+func (c *Client) logout(ctx context.Context, idx int, id uint32, sec []byte) error {
 	rsp := MsgLogoutRsp{}
-	err = ctx.ReadObj(&rsp)
+	stream, err := c.callEndpoint(ctx, idx, "logout", &MsgLogoutReq{SubscriberID: id, SubscriberSecret: sec}, &rsp)
 	if err != nil {
 		return err
 	}
+	stream.Close()
 
 	if len(rsp.Msg) > 0 {
 		return errors.New(rsp.Msg)
@@ -68,19 +173,53 @@ func (c *Client) Logout() error {
 	return nil
 }
 
+// Subscribe replaces all current subscriptions with rooms.
 func (c *Client) Subscribe(rooms []MsgSubscribeRoom) error {
-	ctx, err := toyframe.CallWithInterruptor(c.network, c.address, "subscribe", c.dial, c.ich,
-		&MsgSubscribeReq{SubscriberID: c.id, SubscriberSecret: c.sec, Rooms: rooms})
-	if err != nil {
+	return c.SubscribeContext(context.Background(), rooms)
+}
+
+// SubscribeAdd subscribes rooms and cmds in addition to current subscriptions.
+func (c *Client) SubscribeAdd(rooms []MsgSubscribeRoom) error {
+	return c.SubscribeAddContext(context.Background(), rooms)
+}
+
+// SubscribeRemove unsubscribes listed cmds of rooms, a room with empty Cmds is unsubscribed entirely.
+func (c *Client) SubscribeRemove(rooms []MsgSubscribeRoom) error {
+	return c.SubscribeRemoveContext(context.Background(), rooms)
+}
+
+// SubscribeContext is Subscribe which gives up once ctx is done, subscriptions may have
+// been applied by the server if cancelled while waiting for the response.
+func (c *Client) SubscribeContext(ctx context.Context, rooms []MsgSubscribeRoom) error {
+	return c.callSubscribe(ctx, "subscribe", rooms)
+}
+
+// SubscribeAddContext is SubscribeAdd which gives up once ctx is done.
+func (c *Client) SubscribeAddContext(ctx context.Context, rooms []MsgSubscribeRoom) error {
+	return c.callSubscribe(ctx, "subscribe_add", rooms)
+}
+
+// SubscribeRemoveContext is SubscribeRemove which gives up once ctx is done.
+func (c *Client) SubscribeRemoveContext(ctx context.Context, rooms []MsgSubscribeRoom) error {
+	return c.callSubscribe(ctx, "subscribe_remove", rooms)
+}
+
+func (c *Client) callSubscribe(ctx context.Context, method string, rooms []MsgSubscribeRoom) error {
+	if err := c.subscribe(ctx, -1, method, c.id, c.sec, rooms); err != nil {
 		return err
 	}
-	defer ctx.Close()
+	c.rememberSubscriptions(method, rooms)
+	return nil
+}
 
+// subscribe calls subscribe method of subscriber id on the endpoint of idx
+func (c *Client) subscribe(ctx context.Context, idx int, method string, id uint32, sec []byte, rooms []MsgSubscribeRoom) error {
 	rsp := MsgSubscribeRsp{}
-	err = ctx.ReadObj(&rsp)
+	stream, err := c.callEndpoint(ctx, idx, method, &MsgSubscribeReq{SubscriberID: id, SubscriberSecret: sec, Rooms: rooms}, &rsp)
 	if err != nil {
 		return err
 	}
+	stream.Close()
 
 	if len(rsp.Msg) > 0 {
 		return errors.New(rsp.Msg)
@@ -88,12 +227,181 @@ func (c *Client) Subscribe(rooms []MsgSubscribeRoom) error {
 	return nil
 }
 
-func (c *Client) ReadMessages(ctx *toyframe.Context) ([]MsgSubscribeData, error) {
-	batch := MsgSubscribeBatch{}
-	if err := ctx.ReadObj(&batch); err != nil {
+// Subscriptions returns current subscriptions of the client on the relay server.
+func (c *Client) Subscriptions() ([]MsgSubscription, error) {
+	return c.SubscriptionsContext(context.Background())
+}
+
+// SubscriptionsContext is Subscriptions which gives up once ctx is done.
+func (c *Client) SubscriptionsContext(ctx context.Context) ([]MsgSubscription, error) {
+	rsp := MsgListSubscriptionsRsp{}
+	stream, err := c.call(ctx, "list_subscriptions", &MsgListSubscriptionsReq{SubscriberID: c.id, SubscriberSecret: c.sec}, &rsp)
+	if err != nil {
 		return nil, err
 	}
-	return batch.Msgs, nil
+	stream.Close()
+
+	if len(rsp.Msg) > 0 {
+		return nil, errors.New(rsp.Msg)
+	}
+	return rsp.Rooms, nil
+}
+
+// call invokes method on the endpoint of the last Login and reads its response, the returned
+// stream should be closed by the caller. It is interrupted once ctx is done or the interrupt
+// channel of the client is closed, ctx.Err() is returned in the former case.
+func (c *Client) call(ctx context.Context, method string, req msgp.MarshalSizer, rsp msgp.Unmarshaler) (*toyframe.Context, error) {
+	return c.callEndpoint(ctx, -1, method, req, rsp)
+}
+
+// callEndpoint is call on the endpoint of idx, the endpoint is marked down if failed.
+func (c *Client) callEndpoint(ctx context.Context, idx int, method string, req msgp.MarshalSizer, rsp msgp.Unmarshaler) (*toyframe.Context, error) {
+	ich, release := c.interruptor(ctx)
+	defer release()
+
+	idx, endpoint := c.endpoints.get(idx)
+	stream, err := toyframe.CallWithInterruptor(endpoint.Network, endpoint.Address, method, c.dialer(ctx), ich, req)
+	if err == nil {
+		stream.SetInterruptor(ich)
+		err = stream.ReadObj(rsp)
+		stream.SetInterruptor(nil)
+		if err != nil {
+			stream.Close()
+		}
+	}
+
+	if err != nil {
+		err = contextError(ctx, err)
+		if err != ctx.Err() && err != toyframe.ErrInterrupted {
+			c.endpoints.markDown(idx)
+		}
+		return nil, err
+	}
+	c.endpoints.markUp(idx)
+	return stream, nil
+}
+
+// interruptor returns a channel closed once ctx is done or the interrupt channel of the client
+// is closed, release should be called after the interruptable operation finished.
+func (c *Client) interruptor(ctx context.Context) (chan struct{}, func()) {
+	if ctx.Done() == nil {
+		return c.ich, func() {}
+	}
+
+	ich := make(chan struct{})
+	stop_ch := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.ich:
+		case <-stop_ch:
+			return
+		}
+		close(ich)
+	}()
+	return ich, func() { close(stop_ch) }
+}
+
+// dialer wraps dial function of the client so that dialing gives up once ctx is done,
+// connections established after that are closed immediately.
+func (c *Client) dialer(ctx context.Context) dialer.DialFunc {
+	if ctx.Done() == nil {
+		return c.dial
+	}
+
+	return func(network, address string) (net.Conn, error) {
+		type dialResult struct {
+			conn net.Conn
+			err  error
+		}
+		result_ch := make(chan dialResult, 1)
+		go func() {
+			conn, err := c.dial(network, address)
+			result_ch <- dialResult{conn: conn, err: err}
+		}()
+
+		select {
+		case ret := <-result_ch:
+			return ret.conn, ret.err
+		case <-ctx.Done():
+		}
+
+		go func() {
+			if ret := <-result_ch; ret.err == nil {
+				ret.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+func contextError(ctx context.Context, err error) error {
+	if err == toyframe.ErrInterrupted && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// ReadMessages reads next batch from the login stream, redelivered batches already received
+// are skipped. Lost batches are reported by ReadMessagesChecked.
+func (c *Client) ReadMessages(ctx *toyframe.Context) ([]MsgSubscribeData, error) {
+	msgs, _, err := c.readMessages(ctx)
+	return msgs, err
+}
+
+// ReadMessagesContext is ReadMessages which gives up once ctx is done. The login stream is
+// left in an unknown state if interrupted in the middle of a batch, so it is closed when
+// ctx.Err() is returned.
+func (c *Client) ReadMessagesContext(ctx context.Context, stream *toyframe.Context) ([]MsgSubscribeData, error) {
+	msgs, _, err := c.ReadMessagesChecked(ctx, stream)
+	return msgs, err
+}
+
+// ReadMessagesChecked is ReadMessagesContext which also reports lost batches, gap is not nil
+// along with msgs of the current batch if batches before it were lost.
+func (c *Client) ReadMessagesChecked(ctx context.Context, stream *toyframe.Context) (msgs []MsgSubscribeData, gap *SeqGapError, err error) {
+	ich, release := c.interruptor(ctx)
+	defer release()
+
+	stream.SetInterruptor(ich)
+	msgs, gap, err = c.readMessages(stream)
+	stream.SetInterruptor(nil)
+	if err = contextError(ctx, err); err != nil && err == ctx.Err() {
+		stream.Close()
+	}
+	return
+}
+
+func (c *Client) readMessages(ctx *toyframe.Context) ([]MsgSubscribeData, *SeqGapError, error) {
+	for {
+		batch := MsgSubscribeBatch{}
+		if err := ctx.ReadObj(&batch); err != nil {
+			return nil, nil, err
+		}
+		if batch.Seq == 0 {
+			return batch.Msgs, nil, nil // server without seq support
+		}
+
+		last := c.LastSeq()
+		if batch.Seq <= last {
+			continue // duplicated batch
+		}
+		atomic.StoreUint64(&(c.last_seq), batch.Seq)
+		if batch.Seq > last+1 {
+			return batch.Msgs, &SeqGapError{Last: last, Current: batch.Seq}, nil
+		}
+		return batch.Msgs, nil, nil
+	}
+}
+
+// LastSeq returns seq of the last batch read by ReadMessages.
+func (c *Client) LastSeq() uint64 {
+	return atomic.LoadUint64(&(c.last_seq))
+}
+
+// Ack acks all batches read by ReadMessages through the login stream.
+func (c *Client) Ack(ctx *toyframe.Context) error {
+	return ctx.WriteObj(&MsgAck{Seq: c.LastSeq()})
 }
 
 func (c *Client) ReadBodyBytes(data []byte, body_key string) []byte {
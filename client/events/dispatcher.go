@@ -0,0 +1,66 @@
+package events
+
+// Dispatcher routes room msgs to typed callbacks by cmd. Msgs of cmds without a typed
+// callback set are passed to OnOther, msgs failed to decode are passed to OnError.
+type Dispatcher struct {
+	OnDanmaku      func(room_id int, msg *Danmaku)
+	OnGift         func(room_id int, msg *Gift)
+	OnSuperChat    func(room_id int, msg *SuperChat)
+	OnGuardBuy     func(room_id int, msg *GuardBuy)
+	OnInteractWord func(room_id int, msg *InteractWord)
+	OnLive         func(room_id int, info *RoomInfo)
+	OnPreparing    func(room_id int, info *RoomInfo)
+
+	OnOther func(room_id int, cmd string, data []byte)
+	OnError func(room_id int, cmd string, data []byte, err error)
+}
+
+// Dispatch routes a msg of MSG_TYPE_DATA, e.g. d.Dispatch(msg.RoomID, msg.Cmd, msg.Data).
+// Variant cmds like DANMU_MSG:4:0:2:2:2:0 are routed by their BaseCmd.
+func (d *Dispatcher) Dispatch(room_id int, cmd string, data []byte) {
+	var err error
+	base := BaseCmd(cmd)
+	switch {
+	case base == CMD_DANMU_MSG && d.OnDanmaku != nil:
+		var msg *Danmaku
+		if msg, err = DecodeDanmaku(data); err == nil {
+			d.OnDanmaku(room_id, msg)
+		}
+	case base == CMD_SEND_GIFT && d.OnGift != nil:
+		var msg *Gift
+		if msg, err = DecodeGift(data); err == nil {
+			d.OnGift(room_id, msg)
+		}
+	case base == CMD_SUPER_CHAT_MESSAGE && d.OnSuperChat != nil:
+		var msg *SuperChat
+		if msg, err = DecodeSuperChat(data); err == nil {
+			d.OnSuperChat(room_id, msg)
+		}
+	case base == CMD_GUARD_BUY && d.OnGuardBuy != nil:
+		var msg *GuardBuy
+		if msg, err = DecodeGuardBuy(data); err == nil {
+			d.OnGuardBuy(room_id, msg)
+		}
+	case base == CMD_INTERACT_WORD && d.OnInteractWord != nil:
+		var msg *InteractWord
+		if msg, err = DecodeInteractWord(data); err == nil {
+			d.OnInteractWord(room_id, msg)
+		}
+	case base == CMD_LIVE && d.OnLive != nil:
+		var info *RoomInfo
+		if info, err = DecodeRoomInfo(data); err == nil {
+			d.OnLive(room_id, info)
+		}
+	case base == CMD_PREPARING && d.OnPreparing != nil:
+		var info *RoomInfo
+		if info, err = DecodeRoomInfo(data); err == nil {
+			d.OnPreparing(room_id, info)
+		}
+	case d.OnOther != nil:
+		d.OnOther(room_id, cmd, data)
+	}
+
+	if err != nil && d.OnError != nil {
+		d.OnError(room_id, cmd, data, err)
+	}
+}
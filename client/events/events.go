@@ -0,0 +1,112 @@
+// Package events decodes data of common room msgs relayed by brelay into typed structs, e.g.
+//
+//	if msg.MsgType == client.MSG_TYPE_DATA && events.BaseCmd(msg.Cmd) == events.CMD_DANMU_MSG {
+//		danmaku, err := events.DecodeDanmaku(msg.Data)
+//	}
+//
+// Decoders work on msgs projected by subscription fields as well, absent fields are left zero.
+package events
+
+import (
+	"errors"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	dm "github.com/zerozwt/BLiveDanmaku"
+	"github.com/zerozwt/BLiveDanmaku/cmds"
+)
+
+const (
+	CMD_DANMU_MSG          = dm.CMD_DANMU_MSG
+	CMD_SEND_GIFT          = dm.CMD_SEND_GIFT
+	CMD_SUPER_CHAT_MESSAGE = dm.CMD_SUPER_CHAT_MESSAGE
+	CMD_GUARD_BUY          = dm.CMD_GUARD_BUY
+	CMD_INTERACT_WORD      = dm.CMD_INTERACT_WORD
+	CMD_LIVE               = dm.CMD_LIVE
+	CMD_PREPARING          = dm.CMD_PREPARING
+)
+
+type Danmaku = cmds.DanmakuMsg
+type Gift = cmds.SendGift
+type SuperChat = cmds.SuperChatMessage
+type GuardBuy = cmds.GuardBuy
+type InteractWord = cmds.InteractWord
+
+// RoomInfo is data of LIVE and PREPARING msgs, as well as of MSG_TYPE_WS_CONNECT and
+// MSG_TYPE_WS_DISCONNECT msgs.
+type RoomInfo = dm.RoomInfo
+
+var ErrNoBody = errors.New("msg body not found")
+
+// BaseCmd returns cmd without the variant suffix after the first ':', e.g. DANMU_MSG for
+// DANMU_MSG:4:0:2:2:2:0
+func BaseCmd(cmd string) string {
+	if idx := strings.IndexByte(cmd, ':'); idx >= 0 {
+		return cmd[:idx]
+	}
+	return cmd
+}
+
+// DecodeDanmaku decodes data of a DANMU_MSG
+func DecodeDanmaku(data []byte) (*Danmaku, error) {
+	ret := &Danmaku{}
+	return ret, decodeBody(data, "info", ret)
+}
+
+// DecodeGift decodes data of a SEND_GIFT
+func DecodeGift(data []byte) (*Gift, error) {
+	ret := &Gift{}
+	return ret, decodeBody(data, "data", ret)
+}
+
+// DecodeSuperChat decodes data of a SUPER_CHAT_MESSAGE
+func DecodeSuperChat(data []byte) (*SuperChat, error) {
+	ret := &SuperChat{}
+	return ret, decodeBody(data, "data", ret)
+}
+
+// DecodeGuardBuy decodes data of a GUARD_BUY
+func DecodeGuardBuy(data []byte) (*GuardBuy, error) {
+	ret := &GuardBuy{}
+	return ret, decodeBody(data, "data", ret)
+}
+
+// DecodeInteractWord decodes data of an INTERACT_WORD
+func DecodeInteractWord(data []byte) (*InteractWord, error) {
+	ret := &InteractWord{}
+	return ret, decodeBody(data, "data", ret)
+}
+
+// DecodeRoomInfo decodes room info carried by LIVE, PREPARING and connection state msgs
+func DecodeRoomInfo(data []byte) (*RoomInfo, error) {
+	ret := &RoomInfo{}
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	if err := json.Unmarshal(data, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+type decoder interface {
+	Decode(data []byte) error
+}
+
+func decodeBody(data []byte, body_key string, obj decoder) error {
+	var body []byte
+	iter := jsoniter.ParseBytes(jsoniter.ConfigCompatibleWithStandardLibrary, data)
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+		if key == body_key {
+			body = iter.SkipAndReturnBytes()
+		} else {
+			iter.Skip()
+		}
+		return true
+	})
+	if iter.Error != nil {
+		return iter.Error
+	}
+	if body == nil {
+		return ErrNoBody
+	}
+	return obj.Decode(body)
+}
@@ -0,0 +1,71 @@
+package events
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	danmaku, err := DecodeDanmaku([]byte(`{"cmd":"DANMU_MSG","info":[[0,1,25,16777215,1650000000000],"hello",[42,"user1",0,0,0,10000,1,""],[],[21],["",""]]}`))
+	if err != nil || danmaku.Content != "hello" || danmaku.Sender.UID != 42 || danmaku.Sender.UserName != "user1" || danmaku.Sender.UserLevel != 21 {
+		t.Errorf("decode danmaku failed: %+v %v", danmaku, err)
+	}
+
+	gift, err := DecodeGift([]byte(`{"cmd":"SEND_GIFT","data":{"uid":7,"uname":"user2","giftName":"flower","num":3,"price":100}}`))
+	if err != nil || gift.UID != 7 || gift.GiftName != "flower" || gift.Num != 3 {
+		t.Errorf("decode gift failed: %+v %v", gift, err)
+	}
+
+	// projected by subscription fields
+	sc, err := DecodeSuperChat([]byte(`{"data":{"message":"hi","price":30}}`))
+	if err != nil || sc.Message != "hi" || sc.Price != 30 {
+		t.Errorf("decode super chat failed: %+v %v", sc, err)
+	}
+
+	if _, err := DecodeGuardBuy([]byte(`{"cmd":"GUARD_BUY"}`)); err != ErrNoBody {
+		t.Errorf("decode msg without body returns %v", err)
+	}
+
+	info, err := DecodeRoomInfo([]byte(`{"room_info":{"room_id":1000,"title":"test","live_status":1}}`))
+	if err != nil || info.Base.RoomID != 1000 || info.Base.LiveStatus != 1 {
+		t.Errorf("decode room info failed: %+v %v", info, err)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	result := []string{}
+	d := &Dispatcher{
+		OnDanmaku: func(room_id int, msg *Danmaku) { result = append(result, "danmaku:"+msg.Content) },
+		OnLive:    func(room_id int, info *RoomInfo) { result = append(result, "live:"+info.Base.Title) },
+		OnOther:   func(room_id int, cmd string, data []byte) { result = append(result, "other:"+cmd) },
+		OnError:   func(room_id int, cmd string, data []byte, err error) { result = append(result, "error:"+cmd) },
+	}
+
+	d.Dispatch(1, CMD_DANMU_MSG, []byte(`{"cmd":"DANMU_MSG","info":[[],"hello"]}`))
+	d.Dispatch(1, CMD_LIVE, []byte(`{"room_info":{"title":"test"}}`))
+	d.Dispatch(1, CMD_SEND_GIFT, []byte(`{"cmd":"SEND_GIFT","data":{}}`))
+	d.Dispatch(1, CMD_DANMU_MSG, []byte(`{"cmd":"DANMU_MSG","info":{}}`))
+	d.Dispatch(1, "DANMU_MSG:4:0:2:2:2:0", []byte(`{"cmd":"DANMU_MSG:4:0:2:2:2:0","info":[[],"variant"]}`))
+	d.Dispatch(1, "DANMU_MSG_X", []byte(`{}`))
+
+	expect := []string{"danmaku:hello", "live:test", "other:SEND_GIFT", "error:DANMU_MSG", "danmaku:variant", "other:DANMU_MSG_X"}
+	if len(result) != len(expect) {
+		t.Fatalf("unexpected dispatch result: %v", result)
+	}
+	for idx := range expect {
+		if result[idx] != expect[idx] {
+			t.Errorf("unexpected dispatch result: %v", result)
+		}
+	}
+}
+
+func TestBaseCmd(t *testing.T) {
+	tests := map[string]string{
+		"DANMU_MSG":             "DANMU_MSG",
+		"DANMU_MSG:4:0:2:2:2:0": "DANMU_MSG",
+		"SEND_GIFT:":            "SEND_GIFT",
+		"":                      "",
+	}
+	for cmd, expect := range tests {
+		if got := BaseCmd(cmd); got != expect {
+			t.Errorf("BaseCmd(%q) = %q, expect %q", cmd, got, expect)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// failover policies deciding which endpoint Login tries first, endpoints marked down are
+// tried after healthy ones in either case
+const (
+	FAILOVER_PRIORITY    = "priority"    // in listed order
+	FAILOVER_ROUND_ROBIN = "round_robin" // start from the one after where last Login started
+)
+
+const (
+	endpointDownInterval    = 5 * time.Second
+	endpointMaxDownInterval = time.Minute
+)
+
+var ErrNoEndpoint = errors.New("no endpoint")
+
+// Endpoint is a relay server the client could connect to
+type Endpoint struct {
+	Network string
+	Address string
+}
+
+// EndpointStatus reports health of an endpoint, an endpoint is marked down for a while after
+// a failed call, the interval doubles on consecutive failures.
+type EndpointStatus struct {
+	Endpoint
+	Healthy  bool
+	Failures int // consecutive failures
+	Current  bool
+}
+
+type endpointSet struct {
+	sync.Mutex
+	endpoints []endpointState
+	policy    string
+	current   int // endpoint of the subscriber created by last Login, -1 before that
+	next      int // start of next Login in round robin
+
+	subs []MsgSubscribeRoom // subscriptions re-applied on re-Login, nil for single endpoint
+}
+
+type endpointState struct {
+	Endpoint
+	failures   int
+	down_until time.Time
+}
+
+func newEndpointSet(endpoints []Endpoint) *endpointSet {
+	ret := &endpointSet{policy: FAILOVER_PRIORITY, current: -1}
+	for _, item := range endpoints {
+		ret.endpoints = append(ret.endpoints, endpointState{Endpoint: item})
+	}
+	return ret
+}
+
+// get returns the endpoint of idx, or the current one if idx < 0
+func (s *endpointSet) get(idx int) (int, Endpoint) {
+	s.Lock()
+	defer s.Unlock()
+	if idx < 0 {
+		idx = s.current
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	return idx, s.endpoints[idx].Endpoint
+}
+
+// loginOrder returns indexes of endpoints in the order Login tries them
+func (s *endpointSet) loginOrder() []int {
+	s.Lock()
+	defer s.Unlock()
+
+	start := 0
+	if s.policy == FAILOVER_ROUND_ROBIN {
+		start = s.next
+		s.next = (s.next + 1) % len(s.endpoints)
+	}
+
+	now := time.Now()
+	healthy, down := []int{}, []int{}
+	for i := range s.endpoints {
+		idx := (start + i) % len(s.endpoints)
+		if now.Before(s.endpoints[idx].down_until) {
+			down = append(down, idx)
+		} else {
+			healthy = append(healthy, idx)
+		}
+	}
+	return append(healthy, down...)
+}
+
+func (s *endpointSet) markUp(idx int) {
+	s.Lock()
+	defer s.Unlock()
+	s.endpoints[idx].failures = 0
+	s.endpoints[idx].down_until = time.Time{}
+}
+
+func (s *endpointSet) markDown(idx int) {
+	s.Lock()
+	defer s.Unlock()
+	item := &(s.endpoints[idx])
+	interval := endpointDownInterval << uint(item.failures)
+	if interval > endpointMaxDownInterval || interval <= 0 {
+		interval = endpointMaxDownInterval
+	}
+	item.failures++
+	item.down_until = time.Now().Add(interval)
+}
+
+// resubscriptions returns subscriptions to be re-applied to a new subscriber. They are kept
+// until replaced or logged out, so a failed re-apply is retried by next Login.
+func (s *endpointSet) resubscriptions() []MsgSubscribeRoom {
+	s.Lock()
+	defer s.Unlock()
+	if s.current < 0 {
+		return nil
+	}
+	return s.subs
+}
+
+// setCurrent records the endpoint of a new subscriber
+func (s *endpointSet) setCurrent(idx int) {
+	s.Lock()
+	defer s.Unlock()
+	s.current = idx
+}
+
+func (s *endpointSet) multiple() bool {
+	return len(s.endpoints) > 1
+}
+
+func (s *endpointSet) setSubscriptions(subs []MsgSubscribeRoom) {
+	s.Lock()
+	defer s.Unlock()
+	s.subs = subs
+}
+
+func (s *endpointSet) status() []EndpointStatus {
+	s.Lock()
+	defer s.Unlock()
+	now := time.Now()
+	ret := make([]EndpointStatus, 0, len(s.endpoints))
+	for idx, item := range s.endpoints {
+		ret = append(ret, EndpointStatus{
+			Endpoint: item.Endpoint,
+			Healthy:  !now.Before(item.down_until),
+			Failures: item.failures,
+			Current:  idx == s.current,
+		})
+	}
+	return ret
+}
+
+// SetFailoverPolicy sets one of FAILOVER_* deciding which endpoint Login tries first.
+func (c *Client) SetFailoverPolicy(policy string) {
+	c.endpoints.Lock()
+	defer c.endpoints.Unlock()
+	c.endpoints.policy = policy
+}
+
+// Endpoints returns health of endpoints of the client.
+func (c *Client) Endpoints() []EndpointStatus {
+	return c.endpoints.status()
+}
+
+// rememberSubscriptions keeps current subscriptions of the subscriber to re-apply them after
+// re-Login, rooms of a succeeded request are merged the same way as the server does.
+func (c *Client) rememberSubscriptions(method string, rooms []MsgSubscribeRoom) {
+	if !c.endpoints.multiple() {
+		return
+	}
+	c.endpoints.Lock()
+	defer c.endpoints.Unlock()
+	if method == "subscribe" {
+		c.endpoints.subs = nil
+	}
+	for _, item := range rooms {
+		c.endpoints.mergeSubscription(method, item)
+	}
+}
+
+func (s *endpointSet) mergeSubscription(method string, room MsgSubscribeRoom) {
+	idx := 0
+	for idx < len(s.subs) && s.subs[idx].RoomID != room.RoomID {
+		idx++
+	}
+	room.Backfill, room.Since = 0, 0 // recent msgs are not sent again
+	room.Cmds = append([]string{}, room.Cmds...)
+
+	if method == "subscribe_remove" {
+		if idx == len(s.subs) {
+			return
+		}
+		remain := []string{}
+		for _, cmd := range s.subs[idx].Cmds {
+			if !containsString(room.Cmds, cmd) {
+				remain = append(remain, cmd)
+			}
+		}
+		if len(room.Cmds) > 0 && len(remain) > 0 {
+			s.subs[idx].Cmds = remain
+			return
+		}
+		s.subs = append(s.subs[:idx], s.subs[idx+1:]...)
+		return
+	}
+
+	if idx == len(s.subs) {
+		s.subs = append(s.subs, room)
+		return
+	}
+	if method == "subscribe" {
+		s.subs[idx] = room // later one wins for duplicated rooms
+		return
+	}
+	item := &(s.subs[idx])
+	for _, cmd := range room.Cmds {
+		if !containsString(item.Cmds, cmd) {
+			item.Cmds = append(item.Cmds, cmd)
+		}
+	}
+	if len(room.Filters) > 0 {
+		item.Filters = room.Filters
+	}
+	if len(room.Fields) > 0 {
+		item.Fields = room.Fields
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
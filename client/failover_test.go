@@ -0,0 +1,71 @@
+package client
+
+import "testing"
+
+func TestEndpointLoginOrder(t *testing.T) {
+	check := func(s *endpointSet, expect ...int) {
+		t.Helper()
+		order := s.loginOrder()
+		if len(order) != len(expect) {
+			t.Fatalf("unexpected login order %v, expect %v", order, expect)
+		}
+		for idx := range expect {
+			if order[idx] != expect[idx] {
+				t.Fatalf("unexpected login order %v, expect %v", order, expect)
+			}
+		}
+	}
+
+	s := newEndpointSet([]Endpoint{{"tcp", "a"}, {"tcp", "b"}, {"tcp", "c"}})
+	check(s, 0, 1, 2)
+	check(s, 0, 1, 2)
+
+	// endpoints marked down are tried last
+	s.markDown(0)
+	check(s, 1, 2, 0)
+	s.markUp(0)
+	check(s, 0, 1, 2)
+
+	s.policy = FAILOVER_ROUND_ROBIN
+	check(s, 0, 1, 2)
+	check(s, 1, 2, 0)
+	s.markDown(2)
+	check(s, 0, 1, 2)
+	check(s, 0, 1, 2)
+	if status := s.status(); status[2].Healthy || status[2].Failures != 1 {
+		t.Errorf("endpoint not marked down: %+v", status[2])
+	}
+
+	// subscriptions are re-applied on every login after the first one
+	s.setSubscriptions([]MsgSubscribeRoom{{RoomID: 1}})
+	if subs := s.resubscriptions(); subs != nil {
+		t.Errorf("subscriptions re-applied on first login")
+	}
+	s.setCurrent(0)
+	if subs := s.resubscriptions(); len(subs) != 1 {
+		t.Errorf("subscriptions not re-applied on same endpoint")
+	}
+	s.setCurrent(1)
+	if subs := s.resubscriptions(); len(subs) != 1 {
+		t.Errorf("subscriptions not re-applied after failing over")
+	}
+}
+
+func TestRememberSubscriptions(t *testing.T) {
+	if _, err := NewBRelayClientWithEndpoints("test", nil, nil, nil); err != ErrNoEndpoint {
+		t.Errorf("create client without endpoints returns %v", err)
+	}
+
+	c, _ := NewBRelayClientWithEndpoints("test", []Endpoint{{"tcp", "a"}, {"tcp", "b"}}, nil, nil)
+	c.rememberSubscriptions("subscribe", []MsgSubscribeRoom{{RoomID: 1, Cmds: []string{"A"}, Backfill: 10}, {RoomID: 2, Cmds: []string{"A"}}})
+	c.rememberSubscriptions("subscribe_add", []MsgSubscribeRoom{{RoomID: 1, Cmds: []string{"B"}, Fields: []string{"f"}}, {RoomID: 3, Cmds: []string{"C"}}})
+	c.rememberSubscriptions("subscribe_remove", []MsgSubscribeRoom{{RoomID: 1, Cmds: []string{"A"}}, {RoomID: 2}})
+
+	subs := c.endpoints.subs
+	if len(subs) != 2 || subs[0].RoomID != 1 || subs[1].RoomID != 3 {
+		t.Fatalf("unexpected subscriptions: %+v", subs)
+	}
+	if len(subs[0].Cmds) != 1 || subs[0].Cmds[0] != "B" || len(subs[0].Fields) != 1 || subs[0].Backfill != 0 {
+		t.Errorf("unexpected subscription of room 1: %+v", subs[0])
+	}
+}
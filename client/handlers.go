@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/zerozwt/brelay/client/events"
+	"github.com/zerozwt/toyframe"
+)
+
+// ALL_ROOMS registers a handler for msgs of all rooms
+const ALL_ROOMS = 0
+
+// PanicError is reported to the error handler when a handler panics, the panic does not
+// affect other handlers.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("handler panic: %v", e.Value)
+}
+
+// handlerRegistry holds callbacks keyed on room, MSG_TYPE_* and cmd. Handlers of a msg are
+// called in registration order, each one isolated from panics of the others.
+type handlerRegistry struct {
+	lock     sync.RWMutex
+	entries  []handlerEntry
+	on_error func(msg *MsgSubscribeData, err error)
+}
+
+type handlerEntry struct {
+	room_id  int
+	msg_type byte
+	cmd      string // empty for all cmds
+	fn       func(msg *MsgSubscribeData) error
+}
+
+// On registers fn for msgs of msg_type and cmd in a room, empty cmd matches all cmds. A cmd
+// without ':' matches its variants as well, e.g. DANMU_MSG matches DANMU_MSG:4:0:2:2:2:0.
+func (c *Client) On(room_id int, msg_type byte, cmd string, fn func(msg *MsgSubscribeData)) {
+	c.handlers.add(room_id, msg_type, cmd, func(msg *MsgSubscribeData) error {
+		fn(msg)
+		return nil
+	})
+}
+
+// OnCmd registers fn for room msgs of cmd, empty cmd matches all cmds.
+func (c *Client) OnCmd(room_id int, cmd string, fn func(msg *MsgSubscribeData)) {
+	c.On(room_id, MSG_TYPE_DATA, cmd, fn)
+}
+
+func (c *Client) OnDanmaku(room_id int, fn func(room_id int, msg *events.Danmaku)) {
+	c.handlers.addEvent(room_id, events.CMD_DANMU_MSG, events.Dispatcher{OnDanmaku: fn})
+}
+
+func (c *Client) OnGift(room_id int, fn func(room_id int, msg *events.Gift)) {
+	c.handlers.addEvent(room_id, events.CMD_SEND_GIFT, events.Dispatcher{OnGift: fn})
+}
+
+func (c *Client) OnSuperChat(room_id int, fn func(room_id int, msg *events.SuperChat)) {
+	c.handlers.addEvent(room_id, events.CMD_SUPER_CHAT_MESSAGE, events.Dispatcher{OnSuperChat: fn})
+}
+
+func (c *Client) OnGuardBuy(room_id int, fn func(room_id int, msg *events.GuardBuy)) {
+	c.handlers.addEvent(room_id, events.CMD_GUARD_BUY, events.Dispatcher{OnGuardBuy: fn})
+}
+
+func (c *Client) OnInteractWord(room_id int, fn func(room_id int, msg *events.InteractWord)) {
+	c.handlers.addEvent(room_id, events.CMD_INTERACT_WORD, events.Dispatcher{OnInteractWord: fn})
+}
+
+func (c *Client) OnLive(room_id int, fn func(room_id int, info *events.RoomInfo)) {
+	c.handlers.addEvent(room_id, events.CMD_LIVE, events.Dispatcher{OnLive: fn})
+}
+
+func (c *Client) OnPreparing(room_id int, fn func(room_id int, info *events.RoomInfo)) {
+	c.handlers.addEvent(room_id, events.CMD_PREPARING, events.Dispatcher{OnPreparing: fn})
+}
+
+// OnConnect registers fn called when the upstream connection of a room is established
+func (c *Client) OnConnect(room_id int, fn func(room_id int, info *events.RoomInfo)) {
+	c.handlers.addRoomState(room_id, MSG_TYPE_WS_CONNECT, fn)
+}
+
+// OnDisconnect registers fn called when the upstream connection of a room is interrupted,
+// the server reconnects it automatically.
+func (c *Client) OnDisconnect(room_id int, fn func(room_id int, info *events.RoomInfo)) {
+	c.handlers.addRoomState(room_id, MSG_TYPE_WS_DISCONNECT, fn)
+}
+
+// OnRoomConnFail registers fn called when the server failed to connect a room
+func (c *Client) OnRoomConnFail(room_id int, fn func(room_id int)) {
+	c.handlers.add(room_id, MSG_TYPE_ROOM_CONN_FAIL, "", func(msg *MsgSubscribeData) error {
+		fn(msg.RoomID)
+		return nil
+	})
+}
+
+// OnError registers fn called with msgs failed to decode, panics of handlers as *PanicError,
+// and *SeqGapError with nil msg.
+func (c *Client) OnError(fn func(msg *MsgSubscribeData, err error)) {
+	c.handlers.lock.Lock()
+	defer c.handlers.lock.Unlock()
+	c.handlers.on_error = fn
+}
+
+// Dispatch calls handlers registered on the client for msg, e.g. for msgs received from a
+// Session.
+func (c *Client) Dispatch(msg *MsgSubscribeData) {
+	c.handlers.dispatch(msg)
+}
+
+// addEvent registers a typed callback set in d for room msgs of cmd, decoding is left to
+// events.Dispatcher.
+func (h *handlerRegistry) addEvent(room_id int, cmd string, d events.Dispatcher) {
+	h.add(room_id, MSG_TYPE_DATA, cmd, func(msg *MsgSubscribeData) (err error) {
+		tmp := d
+		tmp.OnError = func(room_id int, cmd string, data []byte, decode_err error) { err = decode_err }
+		tmp.Dispatch(msg.RoomID, msg.Cmd, msg.Data)
+		return
+	})
+}
+
+// addRoomState registers fn for state msgs of msg_type carrying room info
+func (h *handlerRegistry) addRoomState(room_id int, msg_type byte, fn func(room_id int, info *events.RoomInfo)) {
+	h.add(room_id, msg_type, "", func(msg *MsgSubscribeData) error {
+		info, err := events.DecodeRoomInfo(msg.Data)
+		if err == nil {
+			fn(msg.RoomID, info)
+		}
+		return err
+	})
+}
+
+func (h *handlerRegistry) add(room_id int, msg_type byte, cmd string, fn func(msg *MsgSubscribeData) error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.entries = append(h.entries, handlerEntry{room_id: room_id, msg_type: msg_type, cmd: cmd, fn: fn})
+}
+
+func (h *handlerRegistry) dispatch(msg *MsgSubscribeData) {
+	h.lock.RLock()
+	entries := h.entries
+	h.lock.RUnlock()
+
+	for _, item := range entries {
+		if item.msg_type != msg.MsgType ||
+			(item.room_id != ALL_ROOMS && item.room_id != msg.RoomID) ||
+			(len(item.cmd) > 0 && item.cmd != msg.Cmd && item.cmd != events.BaseCmd(msg.Cmd)) {
+			continue
+		}
+		if err := callHandler(item.fn, msg); err != nil {
+			h.reportError(msg, err)
+		}
+	}
+}
+
+func callHandler(fn func(msg *MsgSubscribeData) error, msg *MsgSubscribeData) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = &PanicError{Value: value, Stack: debug.Stack()}
+		}
+	}()
+	return fn(msg)
+}
+
+func (h *handlerRegistry) reportError(msg *MsgSubscribeData, err error) {
+	h.lock.RLock()
+	on_error := h.on_error
+	h.lock.RUnlock()
+	if on_error != nil {
+		callHandler(func(msg *MsgSubscribeData) error {
+			on_error(msg, err)
+			return nil
+		}, msg)
+	}
+}
+
+// Run reads msgs from the login stream and dispatches them to handlers registered on the
+// client until ctx is done or the stream is broken. Batches are acked after dispatched in
+// ack mode.
+func (c *Client) Run(ctx context.Context, stream *toyframe.Context) error {
+	for {
+		msgs, gap, err := c.ReadMessagesChecked(ctx, stream)
+		if err != nil {
+			return err
+		}
+		if gap != nil {
+			c.handlers.reportError(nil, gap)
+		}
+
+		for idx := range msgs {
+			c.handlers.dispatch(&msgs[idx])
+		}
+
+		if c.ack && len(msgs) > 0 {
+			if err := c.Ack(stream); err != nil {
+				return err
+			}
+		}
+	}
+}
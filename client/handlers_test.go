@@ -0,0 +1,38 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zerozwt/brelay/client/events"
+)
+
+func TestDispatchDecodeError(t *testing.T) {
+	c := NewBRelayClient("test", "tcp", "localhost:0", nil, nil)
+	danmaku, failures := 0, 0
+	c.OnDanmaku(ALL_ROOMS, func(room_id int, msg *events.Danmaku) { danmaku++ })
+	c.OnError(func(msg *MsgSubscribeData, err error) { failures++ })
+
+	c.Dispatch(&MsgSubscribeData{RoomID: 1, MsgType: MSG_TYPE_DATA, Cmd: events.CMD_DANMU_MSG, Data: []byte(`{"cmd":"DANMU_MSG","info":[[],"hello"]}`)})
+	c.Dispatch(&MsgSubscribeData{RoomID: 1, MsgType: MSG_TYPE_DATA, Cmd: events.CMD_DANMU_MSG, Data: []byte(`{"cmd":"DANMU_MSG","info":{}}`)})
+	if danmaku != 1 || failures != 1 {
+		t.Errorf("unexpected dispatch result: danmaku %d failures %d", danmaku, failures)
+	}
+}
+
+func TestDispatchVariantCmd(t *testing.T) {
+	c := NewBRelayClient("test", "tcp", "localhost:0", nil, nil)
+	result := []string{}
+	c.OnDanmaku(ALL_ROOMS, func(room_id int, msg *events.Danmaku) { result = append(result, "danmaku:"+msg.Content) })
+	c.OnCmd(ALL_ROOMS, events.CMD_DANMU_MSG, func(msg *MsgSubscribeData) { result = append(result, "cmd:"+msg.Cmd) })
+	c.OnCmd(ALL_ROOMS, "DANMU_MSG:4:0:2:2:2:0", func(msg *MsgSubscribeData) { result = append(result, "variant:"+msg.Cmd) })
+
+	c.Dispatch(&MsgSubscribeData{RoomID: 1, MsgType: MSG_TYPE_DATA, Cmd: "DANMU_MSG:4:0:2:2:2:0", Data: []byte(`{"cmd":"DANMU_MSG:4:0:2:2:2:0","info":[[],"hello"]}`)})
+	c.Dispatch(&MsgSubscribeData{RoomID: 1, MsgType: MSG_TYPE_DATA, Cmd: "DANMU_MSG_X", Data: []byte(`{}`)})
+	c.Dispatch(&MsgSubscribeData{RoomID: 1, MsgType: MSG_TYPE_DATA, Cmd: "DANMU_MSG:1", Data: []byte(`{"cmd":"DANMU_MSG:1","info":[[],"world"]}`)})
+
+	expect := "danmaku:hello,cmd:DANMU_MSG:4:0:2:2:2:0,variant:DANMU_MSG:4:0:2:2:2:0,danmaku:world,cmd:DANMU_MSG:1"
+	if got := strings.Join(result, ","); got != expect {
+		t.Errorf("unexpected dispatch result: %s", got)
+	}
+}
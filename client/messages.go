@@ -3,55 +3,181 @@ package client
 //go:generate msgp
 
 type MsgLoginReq struct {
-	ID string `msg:"id"`
+	ID       string `msg:"id" json:"id"`
+	Ack      bool   `msg:"ack" json:"ack"`           // server retains sent batches until acked with MsgAck
+	Overflow string `msg:"overflow" json:"overflow"` // cache overflow policy, one of OVERFLOW_*, empty for server default
+
+	FlushInterval uint32 `msg:"flush_ms" json:"flush_ms"` // interval in milliseconds of sending batches, 0 for server default
 }
 
 type MsgLoginRsp struct {
-	SubscriberID     uint32 `msg:"sid"`
-	SubscriberSecret []byte `msg:"sec"`
+	SubscriberID     uint32 `msg:"sid" json:"sid"`
+	SubscriberSecret []byte `msg:"sec" json:"sec"`
+}
+
+//------------------------------------------------------------------
+
+type MsgResumeReq struct {
+	ID               string `msg:"id" json:"id"`
+	SubscriberID     uint32 `msg:"sid" json:"sid"`
+	SubscriberSecret []byte `msg:"sec" json:"sec"`
+	LastSeq          uint64 `msg:"last_seq" json:"last_seq"` // seq of last batch received, batches after it are redelivered
+}
+
+type MsgResumeRsp struct {
+	Ok  bool   `msg:"ok" json:"ok"`
+	Msg string `msg:"msg" json:"msg"`
 }
 
 //------------------------------------------------------------------
 
 type MsgLogoutReq struct {
-	SubscriberID     uint32 `msg:"sid"`
-	SubscriberSecret []byte `msg:"sec"`
+	SubscriberID     uint32 `msg:"sid" json:"sid"`
+	SubscriberSecret []byte `msg:"sec" json:"sec"`
 }
 
 type MsgLogoutRsp struct {
-	Ok  bool   `msg:"ok"`
-	Msg string `msg:"msg"`
+	Ok  bool   `msg:"ok" json:"ok"`
+	Msg string `msg:"msg" json:"msg"`
 }
 
 //------------------------------------------------------------------
 
 type MsgSubscribeReq struct {
-	SubscriberID     uint32             `msg:"sid"`
-	SubscriberSecret []byte             `msg:"sec"`
-	Rooms            []MsgSubscribeRoom `msg:"rooms"`
+	SubscriberID     uint32             `msg:"sid" json:"sid"`
+	SubscriberSecret []byte             `msg:"sec" json:"sec"`
+	Rooms            []MsgSubscribeRoom `msg:"rooms" json:"rooms"`
 }
 
 type MsgSubscribeRoom struct {
-	RoomID int      `msg:"room"`
-	Cmds   []string `msg:"cmds"`
+	RoomID   int         `msg:"room" json:"room"`
+	Cmds     []string    `msg:"cmds" json:"cmds"`         // cmd patterns: "CMD", "CMD_PREFIX*", "*" for all, "!PATTERN" to exclude
+	Filters  []MsgFilter `msg:"filters" json:"filters"`   // only msgs passing all filters are sent
+	Fields   []string    `msg:"fields" json:"fields"`     // dot separated json paths kept in msg data, empty for whole data
+	Backfill uint32      `msg:"backfill" json:"backfill"` // send up to N recent msgs kept by server before live msgs
+	Since    int64       `msg:"since" json:"since"`       // send recent msgs since unix timestamp in milliseconds before live msgs
+}
+
+// MsgFilter is evaluated against raw json of room msgs, values are compared numerically if
+// both the json value and Args are numbers, otherwise compared as strings.
+type MsgFilter struct {
+	Cmd  string   `msg:"cmd" json:"cmd"`   // cmd pattern the filter applies to, empty for all cmds
+	Path string   `msg:"path" json:"path"` // dot separated json path, e.g. "info.2.0" or "data.price"
+	Op   string   `msg:"op" json:"op"`     // one of FILTER_OP_*
+	Args []string `msg:"args" json:"args"`
 }
 
 type MsgSubscribeRsp struct {
-	Ok  bool   `msg:"ok"`
-	Msg string `msg:"msg"`
+	Ok  bool   `msg:"ok" json:"ok"`
+	Msg string `msg:"msg" json:"msg"`
+}
+
+//------------------------------------------------------------------
+
+type MsgListSubscriptionsReq struct {
+	SubscriberID     uint32 `msg:"sid" json:"sid"`
+	SubscriberSecret []byte `msg:"sec" json:"sec"`
+}
+
+type MsgListSubscriptionsRsp struct {
+	Ok    bool              `msg:"ok" json:"ok"`
+	Msg   string            `msg:"msg" json:"msg"`
+	Rooms []MsgSubscription `msg:"rooms" json:"rooms"`
+}
+
+type MsgSubscription struct {
+	RoomID  int         `msg:"room" json:"room"`
+	Cmds    []string    `msg:"cmds" json:"cmds"`
+	Filters []MsgFilter `msg:"filters" json:"filters"`
+	Fields  []string    `msg:"fields" json:"fields"`
+	State   byte        `msg:"state" json:"state"` // upstream connection state, one of ROOM_STATE_*
+}
+
+//------------------------------------------------------------------
+
+// admin requests are authenticated by admin key of the server
+
+type MsgAdminListSubscribersReq struct {
+	Key string `msg:"key" json:"key"`
+}
+
+type MsgAdminListSubscribersRsp struct {
+	Ok          bool                 `msg:"ok" json:"ok"`
+	Msg         string               `msg:"msg" json:"msg"`
+	Subscribers []MsgAdminSubscriber `msg:"subscribers" json:"subscribers"`
+}
+
+type MsgAdminSubscriber struct {
+	SubscriberID uint32 `msg:"sid" json:"sid"`
+	Name         string `msg:"name" json:"name"` // MsgLoginReq.ID
+	Addr         string `msg:"addr" json:"addr"` // remote address of the client, empty for server side subscribers
+	Rooms        []int  `msg:"rooms" json:"rooms"`
+	Detached     bool   `msg:"detached" json:"detached"` // login stream broken, waiting for resume
+	Ack          bool   `msg:"ack" json:"ack"`
+	Overflow     string `msg:"overflow" json:"overflow"`
+	CacheMsgs    int    `msg:"cache_msgs" json:"cache_msgs"`
+	CacheBytes   int    `msg:"cache_bytes" json:"cache_bytes"`
+	Unacked      int    `msg:"unacked" json:"unacked"` // batches retained for redelivery
 }
 
+type MsgAdminListRoomsReq struct {
+	Key string `msg:"key" json:"key"`
+}
+
+type MsgAdminListRoomsRsp struct {
+	Ok    bool           `msg:"ok" json:"ok"`
+	Msg   string         `msg:"msg" json:"msg"`
+	Rooms []MsgAdminRoom `msg:"rooms" json:"rooms"`
+}
+
+type MsgAdminRoom struct {
+	RoomID      int    `msg:"room" json:"room"`
+	State       byte   `msg:"state" json:"state"`   // one of ROOM_STATE_*
+	Pinned      bool   `msg:"pinned" json:"pinned"` // kept connected without subscribers, e.g. recorded rooms
+	Idle        bool   `msg:"idle" json:"idle"`     // no subscribers, to be closed after idle timeout
+	Subscribers int    `msg:"subscribers" json:"subscribers"`
+	Info        []byte `msg:"info" json:"info"` // json of room info while connected
+}
+
+type MsgAdminKickReq struct {
+	Key          string `msg:"key" json:"key"`
+	SubscriberID uint32 `msg:"sid" json:"sid"`
+}
+
+type MsgAdminRoomReq struct {
+	Key    string `msg:"key" json:"key"`
+	RoomID int    `msg:"room" json:"room"`
+	Action string `msg:"action" json:"action"` // one of ADMIN_ROOM_*
+}
+
+type MsgAdminRsp struct {
+	Ok  bool   `msg:"ok" json:"ok"`
+	Msg string `msg:"msg" json:"msg"`
+}
+
+const (
+	ADMIN_ROOM_RECONNECT = "reconnect" // reconnect upstream connection of the room
+	ADMIN_ROOM_CLOSE     = "close"     // close upstream connection and drop all subscriptions of the room
+)
+
 //------------------------------------------------------------------
 
 type MsgSubscribeBatch struct {
-	Msgs []MsgSubscribeData `msg:"msgs"`
+	Seq  uint64             `msg:"seq" json:"seq"` // per subscriber monotonically increasing from 1
+	Msgs []MsgSubscribeData `msg:"msgs" json:"msgs"`
+}
+
+// MsgAck is sent by client through the login stream to ack batches up to Seq
+type MsgAck struct {
+	Seq uint64 `msg:"seq" json:"seq"`
 }
 
 type MsgSubscribeData struct {
-	RoomID  int    `msg:"room"`
-	MsgType byte   `msg:"type"`
-	Cmd     string `msg:"cmd"`
-	Data    []byte `msg:"data"`
+	RoomID  int    `msg:"room" json:"room"`
+	MsgType byte   `msg:"type" json:"type"`
+	Cmd     string `msg:"cmd" json:"cmd"`
+	Data    []byte `msg:"data" json:"data"`
+	History bool   `msg:"hist,omitempty" json:"hist,omitempty"` // msg is a backfilled one received before subscription
 }
 
 func (b *MsgSubscribeBatch) CombineWith(other *MsgSubscribeBatch) {
@@ -59,7 +185,7 @@ func (b *MsgSubscribeBatch) CombineWith(other *MsgSubscribeBatch) {
 }
 
 func (b *MsgSubscribeBatch) Clone() MsgSubscribeBatch {
-	return MsgSubscribeBatch{Msgs: append([]MsgSubscribeData{}, b.Msgs...)}
+	return MsgSubscribeBatch{Seq: b.Seq, Msgs: append([]MsgSubscribeData{}, b.Msgs...)}
 }
 
 const (
@@ -67,4 +193,30 @@ const (
 	MSG_TYPE_WS_CONNECT     = 1
 	MSG_TYPE_WS_DISCONNECT  = 2
 	MSG_TYPE_ROOM_CONN_FAIL = 3
+	MSG_TYPE_DROPPED        = 4 // Data is {"count":N}, N msgs were dropped due to cache overflow
+)
+
+// overflow policies applied when cached msgs of a slow subscriber exceed server limits
+const (
+	OVERFLOW_DROP_OLDEST = "drop_oldest"
+	OVERFLOW_DROP_NEWEST = "drop_newest"
+	OVERFLOW_DISCONNECT  = "disconnect"
+	OVERFLOW_COALESCE    = "coalesce" // keep only the latest msg of each room and cmd
+)
+
+const (
+	ROOM_STATE_INIT       = 0
+	ROOM_STATE_CONNECTING = 1
+	ROOM_STATE_CONNECTED  = 2
+)
+
+const (
+	FILTER_OP_EQ     = "eq"
+	FILTER_OP_NE     = "ne" // also true if path not exists
+	FILTER_OP_GT     = "gt"
+	FILTER_OP_GE     = "ge"
+	FILTER_OP_LT     = "lt"
+	FILTER_OP_LE     = "le"
+	FILTER_OP_IN     = "in"
+	FILTER_OP_EXISTS = "exists"
 )
@@ -7,7 +7,7 @@ import (
 )
 
 // DecodeMsg implements msgp.Decodable
-func (z *MsgLoginReq) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *MsgAck) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -24,10 +24,10 @@ func (z *MsgLoginReq) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "id":
-			z.ID, err = dc.ReadString()
+		case "seq":
+			z.Seq, err = dc.ReadUint64()
 			if err != nil {
-				err = msgp.WrapError(err, "ID")
+				err = msgp.WrapError(err, "Seq")
 				return
 			}
 		default:
@@ -42,33 +42,33 @@ func (z *MsgLoginReq) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z MsgLoginReq) EncodeMsg(en *msgp.Writer) (err error) {
+func (z MsgAck) EncodeMsg(en *msgp.Writer) (err error) {
 	// map header, size 1
-	// write "id"
-	err = en.Append(0x81, 0xa2, 0x69, 0x64)
+	// write "seq"
+	err = en.Append(0x81, 0xa3, 0x73, 0x65, 0x71)
 	if err != nil {
 		return
 	}
-	err = en.WriteString(z.ID)
+	err = en.WriteUint64(z.Seq)
 	if err != nil {
-		err = msgp.WrapError(err, "ID")
+		err = msgp.WrapError(err, "Seq")
 		return
 	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z MsgLoginReq) MarshalMsg(b []byte) (o []byte, err error) {
+func (z MsgAck) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// map header, size 1
-	// string "id"
-	o = append(o, 0x81, 0xa2, 0x69, 0x64)
-	o = msgp.AppendString(o, z.ID)
+	// string "seq"
+	o = append(o, 0x81, 0xa3, 0x73, 0x65, 0x71)
+	o = msgp.AppendUint64(o, z.Seq)
 	return
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *MsgLoginReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *MsgAck) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -85,10 +85,10 @@ func (z *MsgLoginReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "id":
-			z.ID, bts, err = msgp.ReadStringBytes(bts)
+		case "seq":
+			z.Seq, bts, err = msgp.ReadUint64Bytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "ID")
+				err = msgp.WrapError(err, "Seq")
 				return
 			}
 		default:
@@ -104,13 +104,13 @@ func (z *MsgLoginReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z MsgLoginReq) Msgsize() (s int) {
-	s = 1 + 3 + msgp.StringPrefixSize + len(z.ID)
+func (z MsgAck) Msgsize() (s int) {
+	s = 1 + 4 + msgp.Uint64Size
 	return
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *MsgLoginRsp) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *MsgAdminKickReq) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -127,16 +127,16 @@ func (z *MsgLoginRsp) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "sid":
-			z.SubscriberID, err = dc.ReadUint32()
+		case "key":
+			z.Key, err = dc.ReadString()
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberID")
+				err = msgp.WrapError(err, "Key")
 				return
 			}
-		case "sec":
-			z.SubscriberSecret, err = dc.ReadBytes(z.SubscriberSecret)
+		case "sid":
+			z.SubscriberID, err = dc.ReadUint32()
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberSecret")
+				err = msgp.WrapError(err, "SubscriberID")
 				return
 			}
 		default:
@@ -151,46 +151,46 @@ func (z *MsgLoginRsp) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z *MsgLoginRsp) EncodeMsg(en *msgp.Writer) (err error) {
+func (z MsgAdminKickReq) EncodeMsg(en *msgp.Writer) (err error) {
 	// map header, size 2
-	// write "sid"
-	err = en.Append(0x82, 0xa3, 0x73, 0x69, 0x64)
+	// write "key"
+	err = en.Append(0x82, 0xa3, 0x6b, 0x65, 0x79)
 	if err != nil {
 		return
 	}
-	err = en.WriteUint32(z.SubscriberID)
+	err = en.WriteString(z.Key)
 	if err != nil {
-		err = msgp.WrapError(err, "SubscriberID")
+		err = msgp.WrapError(err, "Key")
 		return
 	}
-	// write "sec"
-	err = en.Append(0xa3, 0x73, 0x65, 0x63)
+	// write "sid"
+	err = en.Append(0xa3, 0x73, 0x69, 0x64)
 	if err != nil {
 		return
 	}
-	err = en.WriteBytes(z.SubscriberSecret)
+	err = en.WriteUint32(z.SubscriberID)
 	if err != nil {
-		err = msgp.WrapError(err, "SubscriberSecret")
+		err = msgp.WrapError(err, "SubscriberID")
 		return
 	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z *MsgLoginRsp) MarshalMsg(b []byte) (o []byte, err error) {
+func (z MsgAdminKickReq) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// map header, size 2
+	// string "key"
+	o = append(o, 0x82, 0xa3, 0x6b, 0x65, 0x79)
+	o = msgp.AppendString(o, z.Key)
 	// string "sid"
-	o = append(o, 0x82, 0xa3, 0x73, 0x69, 0x64)
+	o = append(o, 0xa3, 0x73, 0x69, 0x64)
 	o = msgp.AppendUint32(o, z.SubscriberID)
-	// string "sec"
-	o = append(o, 0xa3, 0x73, 0x65, 0x63)
-	o = msgp.AppendBytes(o, z.SubscriberSecret)
 	return
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *MsgLoginRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *MsgAdminKickReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -207,16 +207,16 @@ func (z *MsgLoginRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "sid":
-			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
+		case "key":
+			z.Key, bts, err = msgp.ReadStringBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberID")
+				err = msgp.WrapError(err, "Key")
 				return
 			}
-		case "sec":
-			z.SubscriberSecret, bts, err = msgp.ReadBytesBytes(bts, z.SubscriberSecret)
+		case "sid":
+			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberSecret")
+				err = msgp.WrapError(err, "SubscriberID")
 				return
 			}
 		default:
@@ -232,13 +232,13 @@ func (z *MsgLoginRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *MsgLoginRsp) Msgsize() (s int) {
-	s = 1 + 4 + msgp.Uint32Size + 4 + msgp.BytesPrefixSize + len(z.SubscriberSecret)
+func (z MsgAdminKickReq) Msgsize() (s int) {
+	s = 1 + 4 + msgp.StringPrefixSize + len(z.Key) + 4 + msgp.Uint32Size
 	return
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *MsgLogoutReq) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *MsgAdminListRoomsReq) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -255,16 +255,10 @@ func (z *MsgLogoutReq) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "sid":
-			z.SubscriberID, err = dc.ReadUint32()
-			if err != nil {
-				err = msgp.WrapError(err, "SubscriberID")
-				return
-			}
-		case "sec":
-			z.SubscriberSecret, err = dc.ReadBytes(z.SubscriberSecret)
+		case "key":
+			z.Key, err = dc.ReadString()
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberSecret")
+				err = msgp.WrapError(err, "Key")
 				return
 			}
 		default:
@@ -279,46 +273,33 @@ func (z *MsgLogoutReq) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z *MsgLogoutReq) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 2
-	// write "sid"
-	err = en.Append(0x82, 0xa3, 0x73, 0x69, 0x64)
-	if err != nil {
-		return
-	}
-	err = en.WriteUint32(z.SubscriberID)
-	if err != nil {
-		err = msgp.WrapError(err, "SubscriberID")
-		return
-	}
-	// write "sec"
-	err = en.Append(0xa3, 0x73, 0x65, 0x63)
+func (z MsgAdminListRoomsReq) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 1
+	// write "key"
+	err = en.Append(0x81, 0xa3, 0x6b, 0x65, 0x79)
 	if err != nil {
 		return
 	}
-	err = en.WriteBytes(z.SubscriberSecret)
+	err = en.WriteString(z.Key)
 	if err != nil {
-		err = msgp.WrapError(err, "SubscriberSecret")
+		err = msgp.WrapError(err, "Key")
 		return
 	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z *MsgLogoutReq) MarshalMsg(b []byte) (o []byte, err error) {
+func (z MsgAdminListRoomsReq) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 2
-	// string "sid"
-	o = append(o, 0x82, 0xa3, 0x73, 0x69, 0x64)
-	o = msgp.AppendUint32(o, z.SubscriberID)
-	// string "sec"
-	o = append(o, 0xa3, 0x73, 0x65, 0x63)
-	o = msgp.AppendBytes(o, z.SubscriberSecret)
+	// map header, size 1
+	// string "key"
+	o = append(o, 0x81, 0xa3, 0x6b, 0x65, 0x79)
+	o = msgp.AppendString(o, z.Key)
 	return
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *MsgLogoutReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *MsgAdminListRoomsReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -335,16 +316,10 @@ func (z *MsgLogoutReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "sid":
-			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
-			if err != nil {
-				err = msgp.WrapError(err, "SubscriberID")
-				return
-			}
-		case "sec":
-			z.SubscriberSecret, bts, err = msgp.ReadBytesBytes(bts, z.SubscriberSecret)
+		case "key":
+			z.Key, bts, err = msgp.ReadStringBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberSecret")
+				err = msgp.WrapError(err, "Key")
 				return
 			}
 		default:
@@ -360,13 +335,13 @@ func (z *MsgLogoutReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *MsgLogoutReq) Msgsize() (s int) {
-	s = 1 + 4 + msgp.Uint32Size + 4 + msgp.BytesPrefixSize + len(z.SubscriberSecret)
+func (z MsgAdminListRoomsReq) Msgsize() (s int) {
+	s = 1 + 4 + msgp.StringPrefixSize + len(z.Key)
 	return
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *MsgLogoutRsp) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *MsgAdminListRoomsRsp) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -395,6 +370,25 @@ func (z *MsgLogoutRsp) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "Msg")
 				return
 			}
+		case "rooms":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Rooms")
+				return
+			}
+			if cap(z.Rooms) >= int(zb0002) {
+				z.Rooms = (z.Rooms)[:zb0002]
+			} else {
+				z.Rooms = make([]MsgAdminRoom, zb0002)
+			}
+			for za0001 := range z.Rooms {
+				err = z.Rooms[za0001].DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Rooms", za0001)
+					return
+				}
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -407,10 +401,10 @@ func (z *MsgLogoutRsp) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z MsgLogoutRsp) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 2
+func (z *MsgAdminListRoomsRsp) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 3
 	// write "ok"
-	err = en.Append(0x82, 0xa2, 0x6f, 0x6b)
+	err = en.Append(0x83, 0xa2, 0x6f, 0x6b)
 	if err != nil {
 		return
 	}
@@ -429,24 +423,51 @@ func (z MsgLogoutRsp) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "Msg")
 		return
 	}
+	// write "rooms"
+	err = en.Append(0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Rooms)))
+	if err != nil {
+		err = msgp.WrapError(err, "Rooms")
+		return
+	}
+	for za0001 := range z.Rooms {
+		err = z.Rooms[za0001].EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "Rooms", za0001)
+			return
+		}
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z MsgLogoutRsp) MarshalMsg(b []byte) (o []byte, err error) {
+func (z *MsgAdminListRoomsRsp) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 2
+	// map header, size 3
 	// string "ok"
-	o = append(o, 0x82, 0xa2, 0x6f, 0x6b)
+	o = append(o, 0x83, 0xa2, 0x6f, 0x6b)
 	o = msgp.AppendBool(o, z.Ok)
 	// string "msg"
 	o = append(o, 0xa3, 0x6d, 0x73, 0x67)
 	o = msgp.AppendString(o, z.Msg)
+	// string "rooms"
+	o = append(o, 0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Rooms)))
+	for za0001 := range z.Rooms {
+		o, err = z.Rooms[za0001].MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "Rooms", za0001)
+			return
+		}
+	}
 	return
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *MsgLogoutRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *MsgAdminListRoomsRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -475,6 +496,25 @@ func (z *MsgLogoutRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "Msg")
 				return
 			}
+		case "rooms":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Rooms")
+				return
+			}
+			if cap(z.Rooms) >= int(zb0002) {
+				z.Rooms = (z.Rooms)[:zb0002]
+			} else {
+				z.Rooms = make([]MsgAdminRoom, zb0002)
+			}
+			for za0001 := range z.Rooms {
+				bts, err = z.Rooms[za0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Rooms", za0001)
+					return
+				}
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -488,13 +528,16 @@ func (z *MsgLogoutRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z MsgLogoutRsp) Msgsize() (s int) {
-	s = 1 + 3 + msgp.BoolSize + 4 + msgp.StringPrefixSize + len(z.Msg)
+func (z *MsgAdminListRoomsRsp) Msgsize() (s int) {
+	s = 1 + 3 + msgp.BoolSize + 4 + msgp.StringPrefixSize + len(z.Msg) + 6 + msgp.ArrayHeaderSize
+	for za0001 := range z.Rooms {
+		s += z.Rooms[za0001].Msgsize()
+	}
 	return
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *MsgSubscribeBatch) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *MsgAdminListSubscribersReq) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -511,25 +554,12 @@ func (z *MsgSubscribeBatch) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "msgs":
-			var zb0002 uint32
-			zb0002, err = dc.ReadArrayHeader()
+		case "key":
+			z.Key, err = dc.ReadString()
 			if err != nil {
-				err = msgp.WrapError(err, "Msgs")
+				err = msgp.WrapError(err, "Key")
 				return
 			}
-			if cap(z.Msgs) >= int(zb0002) {
-				z.Msgs = (z.Msgs)[:zb0002]
-			} else {
-				z.Msgs = make([]MsgSubscribeData, zb0002)
-			}
-			for za0001 := range z.Msgs {
-				err = z.Msgs[za0001].DecodeMsg(dc)
-				if err != nil {
-					err = msgp.WrapError(err, "Msgs", za0001)
-					return
-				}
-			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -542,47 +572,33 @@ func (z *MsgSubscribeBatch) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z *MsgSubscribeBatch) EncodeMsg(en *msgp.Writer) (err error) {
+func (z MsgAdminListSubscribersReq) EncodeMsg(en *msgp.Writer) (err error) {
 	// map header, size 1
-	// write "msgs"
-	err = en.Append(0x81, 0xa4, 0x6d, 0x73, 0x67, 0x73)
+	// write "key"
+	err = en.Append(0x81, 0xa3, 0x6b, 0x65, 0x79)
 	if err != nil {
 		return
 	}
-	err = en.WriteArrayHeader(uint32(len(z.Msgs)))
+	err = en.WriteString(z.Key)
 	if err != nil {
-		err = msgp.WrapError(err, "Msgs")
+		err = msgp.WrapError(err, "Key")
 		return
 	}
-	for za0001 := range z.Msgs {
-		err = z.Msgs[za0001].EncodeMsg(en)
-		if err != nil {
-			err = msgp.WrapError(err, "Msgs", za0001)
-			return
-		}
-	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z *MsgSubscribeBatch) MarshalMsg(b []byte) (o []byte, err error) {
+func (z MsgAdminListSubscribersReq) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// map header, size 1
-	// string "msgs"
-	o = append(o, 0x81, 0xa4, 0x6d, 0x73, 0x67, 0x73)
-	o = msgp.AppendArrayHeader(o, uint32(len(z.Msgs)))
-	for za0001 := range z.Msgs {
-		o, err = z.Msgs[za0001].MarshalMsg(o)
-		if err != nil {
-			err = msgp.WrapError(err, "Msgs", za0001)
-			return
-		}
-	}
+	// string "key"
+	o = append(o, 0x81, 0xa3, 0x6b, 0x65, 0x79)
+	o = msgp.AppendString(o, z.Key)
 	return
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *MsgSubscribeBatch) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *MsgAdminListSubscribersReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -599,25 +615,12 @@ func (z *MsgSubscribeBatch) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "msgs":
-			var zb0002 uint32
-			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		case "key":
+			z.Key, bts, err = msgp.ReadStringBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "Msgs")
+				err = msgp.WrapError(err, "Key")
 				return
 			}
-			if cap(z.Msgs) >= int(zb0002) {
-				z.Msgs = (z.Msgs)[:zb0002]
-			} else {
-				z.Msgs = make([]MsgSubscribeData, zb0002)
-			}
-			for za0001 := range z.Msgs {
-				bts, err = z.Msgs[za0001].UnmarshalMsg(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "Msgs", za0001)
-					return
-				}
-			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -631,16 +634,13 @@ func (z *MsgSubscribeBatch) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *MsgSubscribeBatch) Msgsize() (s int) {
-	s = 1 + 5 + msgp.ArrayHeaderSize
-	for za0001 := range z.Msgs {
-		s += z.Msgs[za0001].Msgsize()
-	}
+func (z MsgAdminListSubscribersReq) Msgsize() (s int) {
+	s = 1 + 4 + msgp.StringPrefixSize + len(z.Key)
 	return
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *MsgSubscribeData) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *MsgAdminListSubscribersRsp) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -657,32 +657,39 @@ func (z *MsgSubscribeData) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "room":
-			z.RoomID, err = dc.ReadInt()
+		case "ok":
+			z.Ok, err = dc.ReadBool()
 			if err != nil {
-				err = msgp.WrapError(err, "RoomID")
+				err = msgp.WrapError(err, "Ok")
 				return
 			}
-		case "type":
-			z.MsgType, err = dc.ReadByte()
+		case "msg":
+			z.Msg, err = dc.ReadString()
 			if err != nil {
-				err = msgp.WrapError(err, "MsgType")
+				err = msgp.WrapError(err, "Msg")
 				return
 			}
-		case "cmd":
-			z.Cmd, err = dc.ReadString()
+		case "subscribers":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
 			if err != nil {
-				err = msgp.WrapError(err, "Cmd")
+				err = msgp.WrapError(err, "Subscribers")
 				return
 			}
-		case "data":
-			z.Data, err = dc.ReadBytes(z.Data)
-			if err != nil {
-				err = msgp.WrapError(err, "Data")
-				return
+			if cap(z.Subscribers) >= int(zb0002) {
+				z.Subscribers = (z.Subscribers)[:zb0002]
+			} else {
+				z.Subscribers = make([]MsgAdminSubscriber, zb0002)
 			}
-		default:
-			err = dc.Skip()
+			for za0001 := range z.Subscribers {
+				err = z.Subscribers[za0001].DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Subscribers", za0001)
+					return
+				}
+			}
+		default:
+			err = dc.Skip()
 			if err != nil {
 				err = msgp.WrapError(err)
 				return
@@ -693,72 +700,73 @@ func (z *MsgSubscribeData) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z *MsgSubscribeData) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 4
-	// write "room"
-	err = en.Append(0x84, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
-	if err != nil {
-		return
-	}
-	err = en.WriteInt(z.RoomID)
+func (z *MsgAdminListSubscribersRsp) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 3
+	// write "ok"
+	err = en.Append(0x83, 0xa2, 0x6f, 0x6b)
 	if err != nil {
-		err = msgp.WrapError(err, "RoomID")
 		return
 	}
-	// write "type"
-	err = en.Append(0xa4, 0x74, 0x79, 0x70, 0x65)
+	err = en.WriteBool(z.Ok)
 	if err != nil {
+		err = msgp.WrapError(err, "Ok")
 		return
 	}
-	err = en.WriteByte(z.MsgType)
+	// write "msg"
+	err = en.Append(0xa3, 0x6d, 0x73, 0x67)
 	if err != nil {
-		err = msgp.WrapError(err, "MsgType")
 		return
 	}
-	// write "cmd"
-	err = en.Append(0xa3, 0x63, 0x6d, 0x64)
+	err = en.WriteString(z.Msg)
 	if err != nil {
+		err = msgp.WrapError(err, "Msg")
 		return
 	}
-	err = en.WriteString(z.Cmd)
+	// write "subscribers"
+	err = en.Append(0xab, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73)
 	if err != nil {
-		err = msgp.WrapError(err, "Cmd")
 		return
 	}
-	// write "data"
-	err = en.Append(0xa4, 0x64, 0x61, 0x74, 0x61)
+	err = en.WriteArrayHeader(uint32(len(z.Subscribers)))
 	if err != nil {
+		err = msgp.WrapError(err, "Subscribers")
 		return
 	}
-	err = en.WriteBytes(z.Data)
-	if err != nil {
-		err = msgp.WrapError(err, "Data")
-		return
+	for za0001 := range z.Subscribers {
+		err = z.Subscribers[za0001].EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "Subscribers", za0001)
+			return
+		}
 	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z *MsgSubscribeData) MarshalMsg(b []byte) (o []byte, err error) {
+func (z *MsgAdminListSubscribersRsp) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 4
-	// string "room"
-	o = append(o, 0x84, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
-	o = msgp.AppendInt(o, z.RoomID)
-	// string "type"
-	o = append(o, 0xa4, 0x74, 0x79, 0x70, 0x65)
-	o = msgp.AppendByte(o, z.MsgType)
-	// string "cmd"
-	o = append(o, 0xa3, 0x63, 0x6d, 0x64)
-	o = msgp.AppendString(o, z.Cmd)
-	// string "data"
-	o = append(o, 0xa4, 0x64, 0x61, 0x74, 0x61)
-	o = msgp.AppendBytes(o, z.Data)
+	// map header, size 3
+	// string "ok"
+	o = append(o, 0x83, 0xa2, 0x6f, 0x6b)
+	o = msgp.AppendBool(o, z.Ok)
+	// string "msg"
+	o = append(o, 0xa3, 0x6d, 0x73, 0x67)
+	o = msgp.AppendString(o, z.Msg)
+	// string "subscribers"
+	o = append(o, 0xab, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Subscribers)))
+	for za0001 := range z.Subscribers {
+		o, err = z.Subscribers[za0001].MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "Subscribers", za0001)
+			return
+		}
+	}
 	return
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *MsgSubscribeData) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *MsgAdminListSubscribersRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -775,29 +783,36 @@ func (z *MsgSubscribeData) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "room":
-			z.RoomID, bts, err = msgp.ReadIntBytes(bts)
+		case "ok":
+			z.Ok, bts, err = msgp.ReadBoolBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "RoomID")
+				err = msgp.WrapError(err, "Ok")
 				return
 			}
-		case "type":
-			z.MsgType, bts, err = msgp.ReadByteBytes(bts)
+		case "msg":
+			z.Msg, bts, err = msgp.ReadStringBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "MsgType")
+				err = msgp.WrapError(err, "Msg")
 				return
 			}
-		case "cmd":
-			z.Cmd, bts, err = msgp.ReadStringBytes(bts)
+		case "subscribers":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "Cmd")
+				err = msgp.WrapError(err, "Subscribers")
 				return
 			}
-		case "data":
-			z.Data, bts, err = msgp.ReadBytesBytes(bts, z.Data)
-			if err != nil {
-				err = msgp.WrapError(err, "Data")
-				return
+			if cap(z.Subscribers) >= int(zb0002) {
+				z.Subscribers = (z.Subscribers)[:zb0002]
+			} else {
+				z.Subscribers = make([]MsgAdminSubscriber, zb0002)
+			}
+			for za0001 := range z.Subscribers {
+				bts, err = z.Subscribers[za0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Subscribers", za0001)
+					return
+				}
 			}
 		default:
 			bts, err = msgp.Skip(bts)
@@ -812,13 +827,16 @@ func (z *MsgSubscribeData) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *MsgSubscribeData) Msgsize() (s int) {
-	s = 1 + 5 + msgp.IntSize + 5 + msgp.ByteSize + 4 + msgp.StringPrefixSize + len(z.Cmd) + 5 + msgp.BytesPrefixSize + len(z.Data)
+func (z *MsgAdminListSubscribersRsp) Msgsize() (s int) {
+	s = 1 + 3 + msgp.BoolSize + 4 + msgp.StringPrefixSize + len(z.Msg) + 12 + msgp.ArrayHeaderSize
+	for za0001 := range z.Subscribers {
+		s += z.Subscribers[za0001].Msgsize()
+	}
 	return
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *MsgSubscribeReq) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *MsgAdminRoom) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -835,78 +853,41 @@ func (z *MsgSubscribeReq) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "sid":
-			z.SubscriberID, err = dc.ReadUint32()
+		case "room":
+			z.RoomID, err = dc.ReadInt()
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberID")
+				err = msgp.WrapError(err, "RoomID")
 				return
 			}
-		case "sec":
-			z.SubscriberSecret, err = dc.ReadBytes(z.SubscriberSecret)
+		case "state":
+			z.State, err = dc.ReadByte()
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberSecret")
+				err = msgp.WrapError(err, "State")
 				return
 			}
-		case "rooms":
-			var zb0002 uint32
-			zb0002, err = dc.ReadArrayHeader()
+		case "pinned":
+			z.Pinned, err = dc.ReadBool()
 			if err != nil {
-				err = msgp.WrapError(err, "Rooms")
+				err = msgp.WrapError(err, "Pinned")
 				return
 			}
-			if cap(z.Rooms) >= int(zb0002) {
-				z.Rooms = (z.Rooms)[:zb0002]
-			} else {
-				z.Rooms = make([]MsgSubscribeRoom, zb0002)
+		case "idle":
+			z.Idle, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "Idle")
+				return
 			}
-			for za0001 := range z.Rooms {
-				var zb0003 uint32
-				zb0003, err = dc.ReadMapHeader()
-				if err != nil {
-					err = msgp.WrapError(err, "Rooms", za0001)
-					return
-				}
-				for zb0003 > 0 {
-					zb0003--
-					field, err = dc.ReadMapKeyPtr()
-					if err != nil {
-						err = msgp.WrapError(err, "Rooms", za0001)
-						return
-					}
-					switch msgp.UnsafeString(field) {
-					case "room":
-						z.Rooms[za0001].RoomID, err = dc.ReadInt()
-						if err != nil {
-							err = msgp.WrapError(err, "Rooms", za0001, "RoomID")
-							return
-						}
-					case "cmds":
-						var zb0004 uint32
-						zb0004, err = dc.ReadArrayHeader()
-						if err != nil {
-							err = msgp.WrapError(err, "Rooms", za0001, "Cmds")
-							return
-						}
-						if cap(z.Rooms[za0001].Cmds) >= int(zb0004) {
-							z.Rooms[za0001].Cmds = (z.Rooms[za0001].Cmds)[:zb0004]
-						} else {
-							z.Rooms[za0001].Cmds = make([]string, zb0004)
-						}
-						for za0002 := range z.Rooms[za0001].Cmds {
-							z.Rooms[za0001].Cmds[za0002], err = dc.ReadString()
-							if err != nil {
-								err = msgp.WrapError(err, "Rooms", za0001, "Cmds", za0002)
-								return
-							}
-						}
-					default:
-						err = dc.Skip()
-						if err != nil {
-							err = msgp.WrapError(err, "Rooms", za0001)
-							return
-						}
-					}
-				}
+		case "subscribers":
+			z.Subscribers, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "Subscribers")
+				return
+			}
+		case "info":
+			z.Info, err = dc.ReadBytes(z.Info)
+			if err != nil {
+				err = msgp.WrapError(err, "Info")
+				return
 			}
 		default:
 			err = dc.Skip()
@@ -920,101 +901,98 @@ func (z *MsgSubscribeReq) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z *MsgSubscribeReq) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 3
-	// write "sid"
-	err = en.Append(0x83, 0xa3, 0x73, 0x69, 0x64)
+func (z *MsgAdminRoom) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 6
+	// write "room"
+	err = en.Append(0x86, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
 	if err != nil {
 		return
 	}
-	err = en.WriteUint32(z.SubscriberID)
+	err = en.WriteInt(z.RoomID)
 	if err != nil {
-		err = msgp.WrapError(err, "SubscriberID")
+		err = msgp.WrapError(err, "RoomID")
 		return
 	}
-	// write "sec"
-	err = en.Append(0xa3, 0x73, 0x65, 0x63)
+	// write "state"
+	err = en.Append(0xa5, 0x73, 0x74, 0x61, 0x74, 0x65)
 	if err != nil {
 		return
 	}
-	err = en.WriteBytes(z.SubscriberSecret)
+	err = en.WriteByte(z.State)
 	if err != nil {
-		err = msgp.WrapError(err, "SubscriberSecret")
+		err = msgp.WrapError(err, "State")
 		return
 	}
-	// write "rooms"
-	err = en.Append(0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
+	// write "pinned"
+	err = en.Append(0xa6, 0x70, 0x69, 0x6e, 0x6e, 0x65, 0x64)
 	if err != nil {
 		return
 	}
-	err = en.WriteArrayHeader(uint32(len(z.Rooms)))
+	err = en.WriteBool(z.Pinned)
 	if err != nil {
-		err = msgp.WrapError(err, "Rooms")
+		err = msgp.WrapError(err, "Pinned")
 		return
 	}
-	for za0001 := range z.Rooms {
-		// map header, size 2
-		// write "room"
-		err = en.Append(0x82, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
-		if err != nil {
-			return
-		}
-		err = en.WriteInt(z.Rooms[za0001].RoomID)
-		if err != nil {
-			err = msgp.WrapError(err, "Rooms", za0001, "RoomID")
-			return
-		}
-		// write "cmds"
-		err = en.Append(0xa4, 0x63, 0x6d, 0x64, 0x73)
-		if err != nil {
-			return
-		}
-		err = en.WriteArrayHeader(uint32(len(z.Rooms[za0001].Cmds)))
-		if err != nil {
-			err = msgp.WrapError(err, "Rooms", za0001, "Cmds")
-			return
-		}
-		for za0002 := range z.Rooms[za0001].Cmds {
-			err = en.WriteString(z.Rooms[za0001].Cmds[za0002])
-			if err != nil {
-				err = msgp.WrapError(err, "Rooms", za0001, "Cmds", za0002)
-				return
-			}
-		}
+	// write "idle"
+	err = en.Append(0xa4, 0x69, 0x64, 0x6c, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteBool(z.Idle)
+	if err != nil {
+		err = msgp.WrapError(err, "Idle")
+		return
+	}
+	// write "subscribers"
+	err = en.Append(0xab, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.Subscribers)
+	if err != nil {
+		err = msgp.WrapError(err, "Subscribers")
+		return
+	}
+	// write "info"
+	err = en.Append(0xa4, 0x69, 0x6e, 0x66, 0x6f)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.Info)
+	if err != nil {
+		err = msgp.WrapError(err, "Info")
+		return
 	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z *MsgSubscribeReq) MarshalMsg(b []byte) (o []byte, err error) {
+func (z *MsgAdminRoom) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 3
-	// string "sid"
-	o = append(o, 0x83, 0xa3, 0x73, 0x69, 0x64)
-	o = msgp.AppendUint32(o, z.SubscriberID)
-	// string "sec"
-	o = append(o, 0xa3, 0x73, 0x65, 0x63)
-	o = msgp.AppendBytes(o, z.SubscriberSecret)
-	// string "rooms"
-	o = append(o, 0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
-	o = msgp.AppendArrayHeader(o, uint32(len(z.Rooms)))
-	for za0001 := range z.Rooms {
-		// map header, size 2
-		// string "room"
-		o = append(o, 0x82, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
-		o = msgp.AppendInt(o, z.Rooms[za0001].RoomID)
-		// string "cmds"
-		o = append(o, 0xa4, 0x63, 0x6d, 0x64, 0x73)
-		o = msgp.AppendArrayHeader(o, uint32(len(z.Rooms[za0001].Cmds)))
-		for za0002 := range z.Rooms[za0001].Cmds {
-			o = msgp.AppendString(o, z.Rooms[za0001].Cmds[za0002])
-		}
-	}
+	// map header, size 6
+	// string "room"
+	o = append(o, 0x86, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
+	o = msgp.AppendInt(o, z.RoomID)
+	// string "state"
+	o = append(o, 0xa5, 0x73, 0x74, 0x61, 0x74, 0x65)
+	o = msgp.AppendByte(o, z.State)
+	// string "pinned"
+	o = append(o, 0xa6, 0x70, 0x69, 0x6e, 0x6e, 0x65, 0x64)
+	o = msgp.AppendBool(o, z.Pinned)
+	// string "idle"
+	o = append(o, 0xa4, 0x69, 0x64, 0x6c, 0x65)
+	o = msgp.AppendBool(o, z.Idle)
+	// string "subscribers"
+	o = append(o, 0xab, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73)
+	o = msgp.AppendInt(o, z.Subscribers)
+	// string "info"
+	o = append(o, 0xa4, 0x69, 0x6e, 0x66, 0x6f)
+	o = msgp.AppendBytes(o, z.Info)
 	return
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *MsgSubscribeReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *MsgAdminRoom) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -1031,78 +1009,41 @@ func (z *MsgSubscribeReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "sid":
-			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
+		case "room":
+			z.RoomID, bts, err = msgp.ReadIntBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberID")
+				err = msgp.WrapError(err, "RoomID")
 				return
 			}
-		case "sec":
-			z.SubscriberSecret, bts, err = msgp.ReadBytesBytes(bts, z.SubscriberSecret)
+		case "state":
+			z.State, bts, err = msgp.ReadByteBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "SubscriberSecret")
+				err = msgp.WrapError(err, "State")
 				return
 			}
-		case "rooms":
-			var zb0002 uint32
-			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		case "pinned":
+			z.Pinned, bts, err = msgp.ReadBoolBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "Rooms")
+				err = msgp.WrapError(err, "Pinned")
 				return
 			}
-			if cap(z.Rooms) >= int(zb0002) {
-				z.Rooms = (z.Rooms)[:zb0002]
-			} else {
-				z.Rooms = make([]MsgSubscribeRoom, zb0002)
+		case "idle":
+			z.Idle, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Idle")
+				return
 			}
-			for za0001 := range z.Rooms {
-				var zb0003 uint32
-				zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "Rooms", za0001)
-					return
-				}
-				for zb0003 > 0 {
-					zb0003--
-					field, bts, err = msgp.ReadMapKeyZC(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "Rooms", za0001)
-						return
-					}
-					switch msgp.UnsafeString(field) {
-					case "room":
-						z.Rooms[za0001].RoomID, bts, err = msgp.ReadIntBytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "Rooms", za0001, "RoomID")
-							return
-						}
-					case "cmds":
-						var zb0004 uint32
-						zb0004, bts, err = msgp.ReadArrayHeaderBytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "Rooms", za0001, "Cmds")
-							return
-						}
-						if cap(z.Rooms[za0001].Cmds) >= int(zb0004) {
-							z.Rooms[za0001].Cmds = (z.Rooms[za0001].Cmds)[:zb0004]
-						} else {
-							z.Rooms[za0001].Cmds = make([]string, zb0004)
-						}
-						for za0002 := range z.Rooms[za0001].Cmds {
-							z.Rooms[za0001].Cmds[za0002], bts, err = msgp.ReadStringBytes(bts)
-							if err != nil {
-								err = msgp.WrapError(err, "Rooms", za0001, "Cmds", za0002)
-								return
-							}
-						}
-					default:
-						bts, err = msgp.Skip(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "Rooms", za0001)
-							return
-						}
-					}
-				}
+		case "subscribers":
+			z.Subscribers, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Subscribers")
+				return
+			}
+		case "info":
+			z.Info, bts, err = msgp.ReadBytesBytes(bts, z.Info)
+			if err != nil {
+				err = msgp.WrapError(err, "Info")
+				return
 			}
 		default:
 			bts, err = msgp.Skip(bts)
@@ -1117,19 +1058,13 @@ func (z *MsgSubscribeReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *MsgSubscribeReq) Msgsize() (s int) {
-	s = 1 + 4 + msgp.Uint32Size + 4 + msgp.BytesPrefixSize + len(z.SubscriberSecret) + 6 + msgp.ArrayHeaderSize
-	for za0001 := range z.Rooms {
-		s += 1 + 5 + msgp.IntSize + 5 + msgp.ArrayHeaderSize
-		for za0002 := range z.Rooms[za0001].Cmds {
-			s += msgp.StringPrefixSize + len(z.Rooms[za0001].Cmds[za0002])
-		}
-	}
+func (z *MsgAdminRoom) Msgsize() (s int) {
+	s = 1 + 5 + msgp.IntSize + 6 + msgp.ByteSize + 7 + msgp.BoolSize + 5 + msgp.BoolSize + 12 + msgp.IntSize + 5 + msgp.BytesPrefixSize + len(z.Info)
 	return
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *MsgSubscribeRoom) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *MsgAdminRoomReq) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -1146,31 +1081,24 @@ func (z *MsgSubscribeRoom) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
+		case "key":
+			z.Key, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Key")
+				return
+			}
 		case "room":
 			z.RoomID, err = dc.ReadInt()
 			if err != nil {
 				err = msgp.WrapError(err, "RoomID")
 				return
 			}
-		case "cmds":
-			var zb0002 uint32
-			zb0002, err = dc.ReadArrayHeader()
+		case "action":
+			z.Action, err = dc.ReadString()
 			if err != nil {
-				err = msgp.WrapError(err, "Cmds")
+				err = msgp.WrapError(err, "Action")
 				return
 			}
-			if cap(z.Cmds) >= int(zb0002) {
-				z.Cmds = (z.Cmds)[:zb0002]
-			} else {
-				z.Cmds = make([]string, zb0002)
-			}
-			for za0001 := range z.Cmds {
-				z.Cmds[za0001], err = dc.ReadString()
-				if err != nil {
-					err = msgp.WrapError(err, "Cmds", za0001)
-					return
-				}
-			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -1183,56 +1111,59 @@ func (z *MsgSubscribeRoom) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z *MsgSubscribeRoom) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 2
-	// write "room"
-	err = en.Append(0x82, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
+func (z MsgAdminRoomReq) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 3
+	// write "key"
+	err = en.Append(0x83, 0xa3, 0x6b, 0x65, 0x79)
 	if err != nil {
 		return
 	}
-	err = en.WriteInt(z.RoomID)
+	err = en.WriteString(z.Key)
 	if err != nil {
-		err = msgp.WrapError(err, "RoomID")
+		err = msgp.WrapError(err, "Key")
 		return
 	}
-	// write "cmds"
-	err = en.Append(0xa4, 0x63, 0x6d, 0x64, 0x73)
+	// write "room"
+	err = en.Append(0xa4, 0x72, 0x6f, 0x6f, 0x6d)
 	if err != nil {
 		return
 	}
-	err = en.WriteArrayHeader(uint32(len(z.Cmds)))
+	err = en.WriteInt(z.RoomID)
 	if err != nil {
-		err = msgp.WrapError(err, "Cmds")
+		err = msgp.WrapError(err, "RoomID")
 		return
 	}
-	for za0001 := range z.Cmds {
-		err = en.WriteString(z.Cmds[za0001])
-		if err != nil {
-			err = msgp.WrapError(err, "Cmds", za0001)
-			return
-		}
+	// write "action"
+	err = en.Append(0xa6, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Action)
+	if err != nil {
+		err = msgp.WrapError(err, "Action")
+		return
 	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z *MsgSubscribeRoom) MarshalMsg(b []byte) (o []byte, err error) {
+func (z MsgAdminRoomReq) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 2
+	// map header, size 3
+	// string "key"
+	o = append(o, 0x83, 0xa3, 0x6b, 0x65, 0x79)
+	o = msgp.AppendString(o, z.Key)
 	// string "room"
-	o = append(o, 0x82, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
+	o = append(o, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
 	o = msgp.AppendInt(o, z.RoomID)
-	// string "cmds"
-	o = append(o, 0xa4, 0x63, 0x6d, 0x64, 0x73)
-	o = msgp.AppendArrayHeader(o, uint32(len(z.Cmds)))
-	for za0001 := range z.Cmds {
-		o = msgp.AppendString(o, z.Cmds[za0001])
-	}
+	// string "action"
+	o = append(o, 0xa6, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e)
+	o = msgp.AppendString(o, z.Action)
 	return
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *MsgSubscribeRoom) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *MsgAdminRoomReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -1249,31 +1180,24 @@ func (z *MsgSubscribeRoom) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
+		case "key":
+			z.Key, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Key")
+				return
+			}
 		case "room":
 			z.RoomID, bts, err = msgp.ReadIntBytes(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "RoomID")
 				return
 			}
-		case "cmds":
-			var zb0002 uint32
-			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		case "action":
+			z.Action, bts, err = msgp.ReadStringBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "Cmds")
+				err = msgp.WrapError(err, "Action")
 				return
 			}
-			if cap(z.Cmds) >= int(zb0002) {
-				z.Cmds = (z.Cmds)[:zb0002]
-			} else {
-				z.Cmds = make([]string, zb0002)
-			}
-			for za0001 := range z.Cmds {
-				z.Cmds[za0001], bts, err = msgp.ReadStringBytes(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "Cmds", za0001)
-					return
-				}
-			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -1287,16 +1211,13 @@ func (z *MsgSubscribeRoom) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *MsgSubscribeRoom) Msgsize() (s int) {
-	s = 1 + 5 + msgp.IntSize + 5 + msgp.ArrayHeaderSize
-	for za0001 := range z.Cmds {
-		s += msgp.StringPrefixSize + len(z.Cmds[za0001])
-	}
+func (z MsgAdminRoomReq) Msgsize() (s int) {
+	s = 1 + 4 + msgp.StringPrefixSize + len(z.Key) + 5 + msgp.IntSize + 7 + msgp.StringPrefixSize + len(z.Action)
 	return
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *MsgSubscribeRsp) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *MsgAdminRsp) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -1337,7 +1258,7 @@ func (z *MsgSubscribeRsp) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z MsgSubscribeRsp) EncodeMsg(en *msgp.Writer) (err error) {
+func (z MsgAdminRsp) EncodeMsg(en *msgp.Writer) (err error) {
 	// map header, size 2
 	// write "ok"
 	err = en.Append(0x82, 0xa2, 0x6f, 0x6b)
@@ -1363,7 +1284,7 @@ func (z MsgSubscribeRsp) EncodeMsg(en *msgp.Writer) (err error) {
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z MsgSubscribeRsp) MarshalMsg(b []byte) (o []byte, err error) {
+func (z MsgAdminRsp) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// map header, size 2
 	// string "ok"
@@ -1376,7 +1297,7 @@ func (z MsgSubscribeRsp) MarshalMsg(b []byte) (o []byte, err error) {
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *MsgSubscribeRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *MsgAdminRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -1418,7 +1339,3186 @@ func (z *MsgSubscribeRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z MsgSubscribeRsp) Msgsize() (s int) {
+func (z MsgAdminRsp) Msgsize() (s int) {
 	s = 1 + 3 + msgp.BoolSize + 4 + msgp.StringPrefixSize + len(z.Msg)
 	return
 }
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgAdminSubscriber) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, err = dc.ReadUint32()
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "name":
+			z.Name, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Name")
+				return
+			}
+		case "addr":
+			z.Addr, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Addr")
+				return
+			}
+		case "rooms":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Rooms")
+				return
+			}
+			if cap(z.Rooms) >= int(zb0002) {
+				z.Rooms = (z.Rooms)[:zb0002]
+			} else {
+				z.Rooms = make([]int, zb0002)
+			}
+			for za0001 := range z.Rooms {
+				z.Rooms[za0001], err = dc.ReadInt()
+				if err != nil {
+					err = msgp.WrapError(err, "Rooms", za0001)
+					return
+				}
+			}
+		case "detached":
+			z.Detached, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "Detached")
+				return
+			}
+		case "ack":
+			z.Ack, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "Ack")
+				return
+			}
+		case "overflow":
+			z.Overflow, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Overflow")
+				return
+			}
+		case "cache_msgs":
+			z.CacheMsgs, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "CacheMsgs")
+				return
+			}
+		case "cache_bytes":
+			z.CacheBytes, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "CacheBytes")
+				return
+			}
+		case "unacked":
+			z.Unacked, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "Unacked")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgAdminSubscriber) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 10
+	// write "sid"
+	err = en.Append(0x8a, 0xa3, 0x73, 0x69, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.SubscriberID)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberID")
+		return
+	}
+	// write "name"
+	err = en.Append(0xa4, 0x6e, 0x61, 0x6d, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Name)
+	if err != nil {
+		err = msgp.WrapError(err, "Name")
+		return
+	}
+	// write "addr"
+	err = en.Append(0xa4, 0x61, 0x64, 0x64, 0x72)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Addr)
+	if err != nil {
+		err = msgp.WrapError(err, "Addr")
+		return
+	}
+	// write "rooms"
+	err = en.Append(0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Rooms)))
+	if err != nil {
+		err = msgp.WrapError(err, "Rooms")
+		return
+	}
+	for za0001 := range z.Rooms {
+		err = en.WriteInt(z.Rooms[za0001])
+		if err != nil {
+			err = msgp.WrapError(err, "Rooms", za0001)
+			return
+		}
+	}
+	// write "detached"
+	err = en.Append(0xa8, 0x64, 0x65, 0x74, 0x61, 0x63, 0x68, 0x65, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteBool(z.Detached)
+	if err != nil {
+		err = msgp.WrapError(err, "Detached")
+		return
+	}
+	// write "ack"
+	err = en.Append(0xa3, 0x61, 0x63, 0x6b)
+	if err != nil {
+		return
+	}
+	err = en.WriteBool(z.Ack)
+	if err != nil {
+		err = msgp.WrapError(err, "Ack")
+		return
+	}
+	// write "overflow"
+	err = en.Append(0xa8, 0x6f, 0x76, 0x65, 0x72, 0x66, 0x6c, 0x6f, 0x77)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Overflow)
+	if err != nil {
+		err = msgp.WrapError(err, "Overflow")
+		return
+	}
+	// write "cache_msgs"
+	err = en.Append(0xaa, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x6d, 0x73, 0x67, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.CacheMsgs)
+	if err != nil {
+		err = msgp.WrapError(err, "CacheMsgs")
+		return
+	}
+	// write "cache_bytes"
+	err = en.Append(0xab, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.CacheBytes)
+	if err != nil {
+		err = msgp.WrapError(err, "CacheBytes")
+		return
+	}
+	// write "unacked"
+	err = en.Append(0xa7, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.Unacked)
+	if err != nil {
+		err = msgp.WrapError(err, "Unacked")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgAdminSubscriber) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 10
+	// string "sid"
+	o = append(o, 0x8a, 0xa3, 0x73, 0x69, 0x64)
+	o = msgp.AppendUint32(o, z.SubscriberID)
+	// string "name"
+	o = append(o, 0xa4, 0x6e, 0x61, 0x6d, 0x65)
+	o = msgp.AppendString(o, z.Name)
+	// string "addr"
+	o = append(o, 0xa4, 0x61, 0x64, 0x64, 0x72)
+	o = msgp.AppendString(o, z.Addr)
+	// string "rooms"
+	o = append(o, 0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Rooms)))
+	for za0001 := range z.Rooms {
+		o = msgp.AppendInt(o, z.Rooms[za0001])
+	}
+	// string "detached"
+	o = append(o, 0xa8, 0x64, 0x65, 0x74, 0x61, 0x63, 0x68, 0x65, 0x64)
+	o = msgp.AppendBool(o, z.Detached)
+	// string "ack"
+	o = append(o, 0xa3, 0x61, 0x63, 0x6b)
+	o = msgp.AppendBool(o, z.Ack)
+	// string "overflow"
+	o = append(o, 0xa8, 0x6f, 0x76, 0x65, 0x72, 0x66, 0x6c, 0x6f, 0x77)
+	o = msgp.AppendString(o, z.Overflow)
+	// string "cache_msgs"
+	o = append(o, 0xaa, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x6d, 0x73, 0x67, 0x73)
+	o = msgp.AppendInt(o, z.CacheMsgs)
+	// string "cache_bytes"
+	o = append(o, 0xab, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73)
+	o = msgp.AppendInt(o, z.CacheBytes)
+	// string "unacked"
+	o = append(o, 0xa7, 0x75, 0x6e, 0x61, 0x63, 0x6b, 0x65, 0x64)
+	o = msgp.AppendInt(o, z.Unacked)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgAdminSubscriber) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "name":
+			z.Name, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Name")
+				return
+			}
+		case "addr":
+			z.Addr, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Addr")
+				return
+			}
+		case "rooms":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Rooms")
+				return
+			}
+			if cap(z.Rooms) >= int(zb0002) {
+				z.Rooms = (z.Rooms)[:zb0002]
+			} else {
+				z.Rooms = make([]int, zb0002)
+			}
+			for za0001 := range z.Rooms {
+				z.Rooms[za0001], bts, err = msgp.ReadIntBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Rooms", za0001)
+					return
+				}
+			}
+		case "detached":
+			z.Detached, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Detached")
+				return
+			}
+		case "ack":
+			z.Ack, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Ack")
+				return
+			}
+		case "overflow":
+			z.Overflow, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Overflow")
+				return
+			}
+		case "cache_msgs":
+			z.CacheMsgs, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "CacheMsgs")
+				return
+			}
+		case "cache_bytes":
+			z.CacheBytes, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "CacheBytes")
+				return
+			}
+		case "unacked":
+			z.Unacked, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Unacked")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgAdminSubscriber) Msgsize() (s int) {
+	s = 1 + 4 + msgp.Uint32Size + 5 + msgp.StringPrefixSize + len(z.Name) + 5 + msgp.StringPrefixSize + len(z.Addr) + 6 + msgp.ArrayHeaderSize + (len(z.Rooms) * (msgp.IntSize)) + 9 + msgp.BoolSize + 4 + msgp.BoolSize + 9 + msgp.StringPrefixSize + len(z.Overflow) + 11 + msgp.IntSize + 12 + msgp.IntSize + 8 + msgp.IntSize
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgFilter) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "cmd":
+			z.Cmd, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Cmd")
+				return
+			}
+		case "path":
+			z.Path, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Path")
+				return
+			}
+		case "op":
+			z.Op, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Op")
+				return
+			}
+		case "args":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Args")
+				return
+			}
+			if cap(z.Args) >= int(zb0002) {
+				z.Args = (z.Args)[:zb0002]
+			} else {
+				z.Args = make([]string, zb0002)
+			}
+			for za0001 := range z.Args {
+				z.Args[za0001], err = dc.ReadString()
+				if err != nil {
+					err = msgp.WrapError(err, "Args", za0001)
+					return
+				}
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgFilter) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 4
+	// write "cmd"
+	err = en.Append(0x84, 0xa3, 0x63, 0x6d, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Cmd)
+	if err != nil {
+		err = msgp.WrapError(err, "Cmd")
+		return
+	}
+	// write "path"
+	err = en.Append(0xa4, 0x70, 0x61, 0x74, 0x68)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Path)
+	if err != nil {
+		err = msgp.WrapError(err, "Path")
+		return
+	}
+	// write "op"
+	err = en.Append(0xa2, 0x6f, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Op)
+	if err != nil {
+		err = msgp.WrapError(err, "Op")
+		return
+	}
+	// write "args"
+	err = en.Append(0xa4, 0x61, 0x72, 0x67, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Args)))
+	if err != nil {
+		err = msgp.WrapError(err, "Args")
+		return
+	}
+	for za0001 := range z.Args {
+		err = en.WriteString(z.Args[za0001])
+		if err != nil {
+			err = msgp.WrapError(err, "Args", za0001)
+			return
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgFilter) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 4
+	// string "cmd"
+	o = append(o, 0x84, 0xa3, 0x63, 0x6d, 0x64)
+	o = msgp.AppendString(o, z.Cmd)
+	// string "path"
+	o = append(o, 0xa4, 0x70, 0x61, 0x74, 0x68)
+	o = msgp.AppendString(o, z.Path)
+	// string "op"
+	o = append(o, 0xa2, 0x6f, 0x70)
+	o = msgp.AppendString(o, z.Op)
+	// string "args"
+	o = append(o, 0xa4, 0x61, 0x72, 0x67, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Args)))
+	for za0001 := range z.Args {
+		o = msgp.AppendString(o, z.Args[za0001])
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgFilter) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "cmd":
+			z.Cmd, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Cmd")
+				return
+			}
+		case "path":
+			z.Path, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Path")
+				return
+			}
+		case "op":
+			z.Op, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Op")
+				return
+			}
+		case "args":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Args")
+				return
+			}
+			if cap(z.Args) >= int(zb0002) {
+				z.Args = (z.Args)[:zb0002]
+			} else {
+				z.Args = make([]string, zb0002)
+			}
+			for za0001 := range z.Args {
+				z.Args[za0001], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Args", za0001)
+					return
+				}
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgFilter) Msgsize() (s int) {
+	s = 1 + 4 + msgp.StringPrefixSize + len(z.Cmd) + 5 + msgp.StringPrefixSize + len(z.Path) + 3 + msgp.StringPrefixSize + len(z.Op) + 5 + msgp.ArrayHeaderSize
+	for za0001 := range z.Args {
+		s += msgp.StringPrefixSize + len(z.Args[za0001])
+	}
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgListSubscriptionsReq) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, err = dc.ReadUint32()
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, err = dc.ReadBytes(z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgListSubscriptionsReq) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 2
+	// write "sid"
+	err = en.Append(0x82, 0xa3, 0x73, 0x69, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.SubscriberID)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberID")
+		return
+	}
+	// write "sec"
+	err = en.Append(0xa3, 0x73, 0x65, 0x63)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.SubscriberSecret)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberSecret")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgListSubscriptionsReq) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 2
+	// string "sid"
+	o = append(o, 0x82, 0xa3, 0x73, 0x69, 0x64)
+	o = msgp.AppendUint32(o, z.SubscriberID)
+	// string "sec"
+	o = append(o, 0xa3, 0x73, 0x65, 0x63)
+	o = msgp.AppendBytes(o, z.SubscriberSecret)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgListSubscriptionsReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, bts, err = msgp.ReadBytesBytes(bts, z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgListSubscriptionsReq) Msgsize() (s int) {
+	s = 1 + 4 + msgp.Uint32Size + 4 + msgp.BytesPrefixSize + len(z.SubscriberSecret)
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgListSubscriptionsRsp) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ok":
+			z.Ok, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "Ok")
+				return
+			}
+		case "msg":
+			z.Msg, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Msg")
+				return
+			}
+		case "rooms":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Rooms")
+				return
+			}
+			if cap(z.Rooms) >= int(zb0002) {
+				z.Rooms = (z.Rooms)[:zb0002]
+			} else {
+				z.Rooms = make([]MsgSubscription, zb0002)
+			}
+			for za0001 := range z.Rooms {
+				err = z.Rooms[za0001].DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Rooms", za0001)
+					return
+				}
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgListSubscriptionsRsp) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 3
+	// write "ok"
+	err = en.Append(0x83, 0xa2, 0x6f, 0x6b)
+	if err != nil {
+		return
+	}
+	err = en.WriteBool(z.Ok)
+	if err != nil {
+		err = msgp.WrapError(err, "Ok")
+		return
+	}
+	// write "msg"
+	err = en.Append(0xa3, 0x6d, 0x73, 0x67)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Msg)
+	if err != nil {
+		err = msgp.WrapError(err, "Msg")
+		return
+	}
+	// write "rooms"
+	err = en.Append(0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Rooms)))
+	if err != nil {
+		err = msgp.WrapError(err, "Rooms")
+		return
+	}
+	for za0001 := range z.Rooms {
+		err = z.Rooms[za0001].EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "Rooms", za0001)
+			return
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgListSubscriptionsRsp) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 3
+	// string "ok"
+	o = append(o, 0x83, 0xa2, 0x6f, 0x6b)
+	o = msgp.AppendBool(o, z.Ok)
+	// string "msg"
+	o = append(o, 0xa3, 0x6d, 0x73, 0x67)
+	o = msgp.AppendString(o, z.Msg)
+	// string "rooms"
+	o = append(o, 0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Rooms)))
+	for za0001 := range z.Rooms {
+		o, err = z.Rooms[za0001].MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "Rooms", za0001)
+			return
+		}
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgListSubscriptionsRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ok":
+			z.Ok, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Ok")
+				return
+			}
+		case "msg":
+			z.Msg, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Msg")
+				return
+			}
+		case "rooms":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Rooms")
+				return
+			}
+			if cap(z.Rooms) >= int(zb0002) {
+				z.Rooms = (z.Rooms)[:zb0002]
+			} else {
+				z.Rooms = make([]MsgSubscription, zb0002)
+			}
+			for za0001 := range z.Rooms {
+				bts, err = z.Rooms[za0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Rooms", za0001)
+					return
+				}
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgListSubscriptionsRsp) Msgsize() (s int) {
+	s = 1 + 3 + msgp.BoolSize + 4 + msgp.StringPrefixSize + len(z.Msg) + 6 + msgp.ArrayHeaderSize
+	for za0001 := range z.Rooms {
+		s += z.Rooms[za0001].Msgsize()
+	}
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgLoginReq) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "id":
+			z.ID, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "ID")
+				return
+			}
+		case "ack":
+			z.Ack, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "Ack")
+				return
+			}
+		case "overflow":
+			z.Overflow, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Overflow")
+				return
+			}
+		case "flush_ms":
+			z.FlushInterval, err = dc.ReadUint32()
+			if err != nil {
+				err = msgp.WrapError(err, "FlushInterval")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgLoginReq) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 4
+	// write "id"
+	err = en.Append(0x84, 0xa2, 0x69, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.ID)
+	if err != nil {
+		err = msgp.WrapError(err, "ID")
+		return
+	}
+	// write "ack"
+	err = en.Append(0xa3, 0x61, 0x63, 0x6b)
+	if err != nil {
+		return
+	}
+	err = en.WriteBool(z.Ack)
+	if err != nil {
+		err = msgp.WrapError(err, "Ack")
+		return
+	}
+	// write "overflow"
+	err = en.Append(0xa8, 0x6f, 0x76, 0x65, 0x72, 0x66, 0x6c, 0x6f, 0x77)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Overflow)
+	if err != nil {
+		err = msgp.WrapError(err, "Overflow")
+		return
+	}
+	// write "flush_ms"
+	err = en.Append(0xa8, 0x66, 0x6c, 0x75, 0x73, 0x68, 0x5f, 0x6d, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.FlushInterval)
+	if err != nil {
+		err = msgp.WrapError(err, "FlushInterval")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgLoginReq) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 4
+	// string "id"
+	o = append(o, 0x84, 0xa2, 0x69, 0x64)
+	o = msgp.AppendString(o, z.ID)
+	// string "ack"
+	o = append(o, 0xa3, 0x61, 0x63, 0x6b)
+	o = msgp.AppendBool(o, z.Ack)
+	// string "overflow"
+	o = append(o, 0xa8, 0x6f, 0x76, 0x65, 0x72, 0x66, 0x6c, 0x6f, 0x77)
+	o = msgp.AppendString(o, z.Overflow)
+	// string "flush_ms"
+	o = append(o, 0xa8, 0x66, 0x6c, 0x75, 0x73, 0x68, 0x5f, 0x6d, 0x73)
+	o = msgp.AppendUint32(o, z.FlushInterval)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgLoginReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "id":
+			z.ID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "ID")
+				return
+			}
+		case "ack":
+			z.Ack, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Ack")
+				return
+			}
+		case "overflow":
+			z.Overflow, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Overflow")
+				return
+			}
+		case "flush_ms":
+			z.FlushInterval, bts, err = msgp.ReadUint32Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "FlushInterval")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgLoginReq) Msgsize() (s int) {
+	s = 1 + 3 + msgp.StringPrefixSize + len(z.ID) + 4 + msgp.BoolSize + 9 + msgp.StringPrefixSize + len(z.Overflow) + 9 + msgp.Uint32Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgLoginRsp) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, err = dc.ReadUint32()
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, err = dc.ReadBytes(z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgLoginRsp) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 2
+	// write "sid"
+	err = en.Append(0x82, 0xa3, 0x73, 0x69, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.SubscriberID)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberID")
+		return
+	}
+	// write "sec"
+	err = en.Append(0xa3, 0x73, 0x65, 0x63)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.SubscriberSecret)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberSecret")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgLoginRsp) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 2
+	// string "sid"
+	o = append(o, 0x82, 0xa3, 0x73, 0x69, 0x64)
+	o = msgp.AppendUint32(o, z.SubscriberID)
+	// string "sec"
+	o = append(o, 0xa3, 0x73, 0x65, 0x63)
+	o = msgp.AppendBytes(o, z.SubscriberSecret)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgLoginRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, bts, err = msgp.ReadBytesBytes(bts, z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgLoginRsp) Msgsize() (s int) {
+	s = 1 + 4 + msgp.Uint32Size + 4 + msgp.BytesPrefixSize + len(z.SubscriberSecret)
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgLogoutReq) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, err = dc.ReadUint32()
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, err = dc.ReadBytes(z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgLogoutReq) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 2
+	// write "sid"
+	err = en.Append(0x82, 0xa3, 0x73, 0x69, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.SubscriberID)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberID")
+		return
+	}
+	// write "sec"
+	err = en.Append(0xa3, 0x73, 0x65, 0x63)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.SubscriberSecret)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberSecret")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgLogoutReq) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 2
+	// string "sid"
+	o = append(o, 0x82, 0xa3, 0x73, 0x69, 0x64)
+	o = msgp.AppendUint32(o, z.SubscriberID)
+	// string "sec"
+	o = append(o, 0xa3, 0x73, 0x65, 0x63)
+	o = msgp.AppendBytes(o, z.SubscriberSecret)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgLogoutReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, bts, err = msgp.ReadBytesBytes(bts, z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgLogoutReq) Msgsize() (s int) {
+	s = 1 + 4 + msgp.Uint32Size + 4 + msgp.BytesPrefixSize + len(z.SubscriberSecret)
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgLogoutRsp) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ok":
+			z.Ok, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "Ok")
+				return
+			}
+		case "msg":
+			z.Msg, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Msg")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z MsgLogoutRsp) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 2
+	// write "ok"
+	err = en.Append(0x82, 0xa2, 0x6f, 0x6b)
+	if err != nil {
+		return
+	}
+	err = en.WriteBool(z.Ok)
+	if err != nil {
+		err = msgp.WrapError(err, "Ok")
+		return
+	}
+	// write "msg"
+	err = en.Append(0xa3, 0x6d, 0x73, 0x67)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Msg)
+	if err != nil {
+		err = msgp.WrapError(err, "Msg")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z MsgLogoutRsp) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 2
+	// string "ok"
+	o = append(o, 0x82, 0xa2, 0x6f, 0x6b)
+	o = msgp.AppendBool(o, z.Ok)
+	// string "msg"
+	o = append(o, 0xa3, 0x6d, 0x73, 0x67)
+	o = msgp.AppendString(o, z.Msg)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgLogoutRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ok":
+			z.Ok, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Ok")
+				return
+			}
+		case "msg":
+			z.Msg, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Msg")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z MsgLogoutRsp) Msgsize() (s int) {
+	s = 1 + 3 + msgp.BoolSize + 4 + msgp.StringPrefixSize + len(z.Msg)
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgResumeReq) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "id":
+			z.ID, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "ID")
+				return
+			}
+		case "sid":
+			z.SubscriberID, err = dc.ReadUint32()
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, err = dc.ReadBytes(z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		case "last_seq":
+			z.LastSeq, err = dc.ReadUint64()
+			if err != nil {
+				err = msgp.WrapError(err, "LastSeq")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgResumeReq) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 4
+	// write "id"
+	err = en.Append(0x84, 0xa2, 0x69, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.ID)
+	if err != nil {
+		err = msgp.WrapError(err, "ID")
+		return
+	}
+	// write "sid"
+	err = en.Append(0xa3, 0x73, 0x69, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.SubscriberID)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberID")
+		return
+	}
+	// write "sec"
+	err = en.Append(0xa3, 0x73, 0x65, 0x63)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.SubscriberSecret)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberSecret")
+		return
+	}
+	// write "last_seq"
+	err = en.Append(0xa8, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x71)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.LastSeq)
+	if err != nil {
+		err = msgp.WrapError(err, "LastSeq")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgResumeReq) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 4
+	// string "id"
+	o = append(o, 0x84, 0xa2, 0x69, 0x64)
+	o = msgp.AppendString(o, z.ID)
+	// string "sid"
+	o = append(o, 0xa3, 0x73, 0x69, 0x64)
+	o = msgp.AppendUint32(o, z.SubscriberID)
+	// string "sec"
+	o = append(o, 0xa3, 0x73, 0x65, 0x63)
+	o = msgp.AppendBytes(o, z.SubscriberSecret)
+	// string "last_seq"
+	o = append(o, 0xa8, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x71)
+	o = msgp.AppendUint64(o, z.LastSeq)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgResumeReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "id":
+			z.ID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "ID")
+				return
+			}
+		case "sid":
+			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, bts, err = msgp.ReadBytesBytes(bts, z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		case "last_seq":
+			z.LastSeq, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "LastSeq")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgResumeReq) Msgsize() (s int) {
+	s = 1 + 3 + msgp.StringPrefixSize + len(z.ID) + 4 + msgp.Uint32Size + 4 + msgp.BytesPrefixSize + len(z.SubscriberSecret) + 9 + msgp.Uint64Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgResumeRsp) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ok":
+			z.Ok, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "Ok")
+				return
+			}
+		case "msg":
+			z.Msg, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Msg")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z MsgResumeRsp) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 2
+	// write "ok"
+	err = en.Append(0x82, 0xa2, 0x6f, 0x6b)
+	if err != nil {
+		return
+	}
+	err = en.WriteBool(z.Ok)
+	if err != nil {
+		err = msgp.WrapError(err, "Ok")
+		return
+	}
+	// write "msg"
+	err = en.Append(0xa3, 0x6d, 0x73, 0x67)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Msg)
+	if err != nil {
+		err = msgp.WrapError(err, "Msg")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z MsgResumeRsp) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 2
+	// string "ok"
+	o = append(o, 0x82, 0xa2, 0x6f, 0x6b)
+	o = msgp.AppendBool(o, z.Ok)
+	// string "msg"
+	o = append(o, 0xa3, 0x6d, 0x73, 0x67)
+	o = msgp.AppendString(o, z.Msg)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgResumeRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ok":
+			z.Ok, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Ok")
+				return
+			}
+		case "msg":
+			z.Msg, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Msg")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z MsgResumeRsp) Msgsize() (s int) {
+	s = 1 + 3 + msgp.BoolSize + 4 + msgp.StringPrefixSize + len(z.Msg)
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgSubscribeBatch) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "seq":
+			z.Seq, err = dc.ReadUint64()
+			if err != nil {
+				err = msgp.WrapError(err, "Seq")
+				return
+			}
+		case "msgs":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Msgs")
+				return
+			}
+			if cap(z.Msgs) >= int(zb0002) {
+				z.Msgs = (z.Msgs)[:zb0002]
+			} else {
+				z.Msgs = make([]MsgSubscribeData, zb0002)
+			}
+			for za0001 := range z.Msgs {
+				err = z.Msgs[za0001].DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Msgs", za0001)
+					return
+				}
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgSubscribeBatch) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 2
+	// write "seq"
+	err = en.Append(0x82, 0xa3, 0x73, 0x65, 0x71)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Seq)
+	if err != nil {
+		err = msgp.WrapError(err, "Seq")
+		return
+	}
+	// write "msgs"
+	err = en.Append(0xa4, 0x6d, 0x73, 0x67, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Msgs)))
+	if err != nil {
+		err = msgp.WrapError(err, "Msgs")
+		return
+	}
+	for za0001 := range z.Msgs {
+		err = z.Msgs[za0001].EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "Msgs", za0001)
+			return
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgSubscribeBatch) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 2
+	// string "seq"
+	o = append(o, 0x82, 0xa3, 0x73, 0x65, 0x71)
+	o = msgp.AppendUint64(o, z.Seq)
+	// string "msgs"
+	o = append(o, 0xa4, 0x6d, 0x73, 0x67, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Msgs)))
+	for za0001 := range z.Msgs {
+		o, err = z.Msgs[za0001].MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "Msgs", za0001)
+			return
+		}
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgSubscribeBatch) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "seq":
+			z.Seq, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Seq")
+				return
+			}
+		case "msgs":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Msgs")
+				return
+			}
+			if cap(z.Msgs) >= int(zb0002) {
+				z.Msgs = (z.Msgs)[:zb0002]
+			} else {
+				z.Msgs = make([]MsgSubscribeData, zb0002)
+			}
+			for za0001 := range z.Msgs {
+				bts, err = z.Msgs[za0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Msgs", za0001)
+					return
+				}
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgSubscribeBatch) Msgsize() (s int) {
+	s = 1 + 4 + msgp.Uint64Size + 5 + msgp.ArrayHeaderSize
+	for za0001 := range z.Msgs {
+		s += z.Msgs[za0001].Msgsize()
+	}
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgSubscribeData) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "room":
+			z.RoomID, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "RoomID")
+				return
+			}
+		case "type":
+			z.MsgType, err = dc.ReadByte()
+			if err != nil {
+				err = msgp.WrapError(err, "MsgType")
+				return
+			}
+		case "cmd":
+			z.Cmd, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Cmd")
+				return
+			}
+		case "data":
+			z.Data, err = dc.ReadBytes(z.Data)
+			if err != nil {
+				err = msgp.WrapError(err, "Data")
+				return
+			}
+		case "hist":
+			z.History, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "History")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgSubscribeData) EncodeMsg(en *msgp.Writer) (err error) {
+	// omitempty: check for empty values
+	zb0001Len := uint32(5)
+	var zb0001Mask uint8 /* 5 bits */
+	if z.History == false {
+		zb0001Len--
+		zb0001Mask |= 0x10
+	}
+	// variable map header, size zb0001Len
+	err = en.Append(0x80 | uint8(zb0001Len))
+	if err != nil {
+		return
+	}
+	if zb0001Len == 0 {
+		return
+	}
+	// write "room"
+	err = en.Append(0xa4, 0x72, 0x6f, 0x6f, 0x6d)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.RoomID)
+	if err != nil {
+		err = msgp.WrapError(err, "RoomID")
+		return
+	}
+	// write "type"
+	err = en.Append(0xa4, 0x74, 0x79, 0x70, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteByte(z.MsgType)
+	if err != nil {
+		err = msgp.WrapError(err, "MsgType")
+		return
+	}
+	// write "cmd"
+	err = en.Append(0xa3, 0x63, 0x6d, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Cmd)
+	if err != nil {
+		err = msgp.WrapError(err, "Cmd")
+		return
+	}
+	// write "data"
+	err = en.Append(0xa4, 0x64, 0x61, 0x74, 0x61)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.Data)
+	if err != nil {
+		err = msgp.WrapError(err, "Data")
+		return
+	}
+	if (zb0001Mask & 0x10) == 0 { // if not empty
+		// write "hist"
+		err = en.Append(0xa4, 0x68, 0x69, 0x73, 0x74)
+		if err != nil {
+			return
+		}
+		err = en.WriteBool(z.History)
+		if err != nil {
+			err = msgp.WrapError(err, "History")
+			return
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgSubscribeData) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0001Len := uint32(5)
+	var zb0001Mask uint8 /* 5 bits */
+	if z.History == false {
+		zb0001Len--
+		zb0001Mask |= 0x10
+	}
+	// variable map header, size zb0001Len
+	o = append(o, 0x80|uint8(zb0001Len))
+	if zb0001Len == 0 {
+		return
+	}
+	// string "room"
+	o = append(o, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
+	o = msgp.AppendInt(o, z.RoomID)
+	// string "type"
+	o = append(o, 0xa4, 0x74, 0x79, 0x70, 0x65)
+	o = msgp.AppendByte(o, z.MsgType)
+	// string "cmd"
+	o = append(o, 0xa3, 0x63, 0x6d, 0x64)
+	o = msgp.AppendString(o, z.Cmd)
+	// string "data"
+	o = append(o, 0xa4, 0x64, 0x61, 0x74, 0x61)
+	o = msgp.AppendBytes(o, z.Data)
+	if (zb0001Mask & 0x10) == 0 { // if not empty
+		// string "hist"
+		o = append(o, 0xa4, 0x68, 0x69, 0x73, 0x74)
+		o = msgp.AppendBool(o, z.History)
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgSubscribeData) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "room":
+			z.RoomID, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "RoomID")
+				return
+			}
+		case "type":
+			z.MsgType, bts, err = msgp.ReadByteBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "MsgType")
+				return
+			}
+		case "cmd":
+			z.Cmd, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Cmd")
+				return
+			}
+		case "data":
+			z.Data, bts, err = msgp.ReadBytesBytes(bts, z.Data)
+			if err != nil {
+				err = msgp.WrapError(err, "Data")
+				return
+			}
+		case "hist":
+			z.History, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "History")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgSubscribeData) Msgsize() (s int) {
+	s = 1 + 5 + msgp.IntSize + 5 + msgp.ByteSize + 4 + msgp.StringPrefixSize + len(z.Cmd) + 5 + msgp.BytesPrefixSize + len(z.Data) + 5 + msgp.BoolSize
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgSubscribeReq) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, err = dc.ReadUint32()
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, err = dc.ReadBytes(z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		case "rooms":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Rooms")
+				return
+			}
+			if cap(z.Rooms) >= int(zb0002) {
+				z.Rooms = (z.Rooms)[:zb0002]
+			} else {
+				z.Rooms = make([]MsgSubscribeRoom, zb0002)
+			}
+			for za0001 := range z.Rooms {
+				err = z.Rooms[za0001].DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Rooms", za0001)
+					return
+				}
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgSubscribeReq) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 3
+	// write "sid"
+	err = en.Append(0x83, 0xa3, 0x73, 0x69, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.SubscriberID)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberID")
+		return
+	}
+	// write "sec"
+	err = en.Append(0xa3, 0x73, 0x65, 0x63)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.SubscriberSecret)
+	if err != nil {
+		err = msgp.WrapError(err, "SubscriberSecret")
+		return
+	}
+	// write "rooms"
+	err = en.Append(0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Rooms)))
+	if err != nil {
+		err = msgp.WrapError(err, "Rooms")
+		return
+	}
+	for za0001 := range z.Rooms {
+		err = z.Rooms[za0001].EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "Rooms", za0001)
+			return
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgSubscribeReq) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 3
+	// string "sid"
+	o = append(o, 0x83, 0xa3, 0x73, 0x69, 0x64)
+	o = msgp.AppendUint32(o, z.SubscriberID)
+	// string "sec"
+	o = append(o, 0xa3, 0x73, 0x65, 0x63)
+	o = msgp.AppendBytes(o, z.SubscriberSecret)
+	// string "rooms"
+	o = append(o, 0xa5, 0x72, 0x6f, 0x6f, 0x6d, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Rooms)))
+	for za0001 := range z.Rooms {
+		o, err = z.Rooms[za0001].MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "Rooms", za0001)
+			return
+		}
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgSubscribeReq) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "sid":
+			z.SubscriberID, bts, err = msgp.ReadUint32Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberID")
+				return
+			}
+		case "sec":
+			z.SubscriberSecret, bts, err = msgp.ReadBytesBytes(bts, z.SubscriberSecret)
+			if err != nil {
+				err = msgp.WrapError(err, "SubscriberSecret")
+				return
+			}
+		case "rooms":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Rooms")
+				return
+			}
+			if cap(z.Rooms) >= int(zb0002) {
+				z.Rooms = (z.Rooms)[:zb0002]
+			} else {
+				z.Rooms = make([]MsgSubscribeRoom, zb0002)
+			}
+			for za0001 := range z.Rooms {
+				bts, err = z.Rooms[za0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Rooms", za0001)
+					return
+				}
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgSubscribeReq) Msgsize() (s int) {
+	s = 1 + 4 + msgp.Uint32Size + 4 + msgp.BytesPrefixSize + len(z.SubscriberSecret) + 6 + msgp.ArrayHeaderSize
+	for za0001 := range z.Rooms {
+		s += z.Rooms[za0001].Msgsize()
+	}
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgSubscribeRoom) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "room":
+			z.RoomID, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "RoomID")
+				return
+			}
+		case "cmds":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Cmds")
+				return
+			}
+			if cap(z.Cmds) >= int(zb0002) {
+				z.Cmds = (z.Cmds)[:zb0002]
+			} else {
+				z.Cmds = make([]string, zb0002)
+			}
+			for za0001 := range z.Cmds {
+				z.Cmds[za0001], err = dc.ReadString()
+				if err != nil {
+					err = msgp.WrapError(err, "Cmds", za0001)
+					return
+				}
+			}
+		case "filters":
+			var zb0003 uint32
+			zb0003, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Filters")
+				return
+			}
+			if cap(z.Filters) >= int(zb0003) {
+				z.Filters = (z.Filters)[:zb0003]
+			} else {
+				z.Filters = make([]MsgFilter, zb0003)
+			}
+			for za0002 := range z.Filters {
+				err = z.Filters[za0002].DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Filters", za0002)
+					return
+				}
+			}
+		case "fields":
+			var zb0004 uint32
+			zb0004, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Fields")
+				return
+			}
+			if cap(z.Fields) >= int(zb0004) {
+				z.Fields = (z.Fields)[:zb0004]
+			} else {
+				z.Fields = make([]string, zb0004)
+			}
+			for za0003 := range z.Fields {
+				z.Fields[za0003], err = dc.ReadString()
+				if err != nil {
+					err = msgp.WrapError(err, "Fields", za0003)
+					return
+				}
+			}
+		case "backfill":
+			z.Backfill, err = dc.ReadUint32()
+			if err != nil {
+				err = msgp.WrapError(err, "Backfill")
+				return
+			}
+		case "since":
+			z.Since, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "Since")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgSubscribeRoom) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 6
+	// write "room"
+	err = en.Append(0x86, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.RoomID)
+	if err != nil {
+		err = msgp.WrapError(err, "RoomID")
+		return
+	}
+	// write "cmds"
+	err = en.Append(0xa4, 0x63, 0x6d, 0x64, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Cmds)))
+	if err != nil {
+		err = msgp.WrapError(err, "Cmds")
+		return
+	}
+	for za0001 := range z.Cmds {
+		err = en.WriteString(z.Cmds[za0001])
+		if err != nil {
+			err = msgp.WrapError(err, "Cmds", za0001)
+			return
+		}
+	}
+	// write "filters"
+	err = en.Append(0xa7, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Filters)))
+	if err != nil {
+		err = msgp.WrapError(err, "Filters")
+		return
+	}
+	for za0002 := range z.Filters {
+		err = z.Filters[za0002].EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "Filters", za0002)
+			return
+		}
+	}
+	// write "fields"
+	err = en.Append(0xa6, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Fields)))
+	if err != nil {
+		err = msgp.WrapError(err, "Fields")
+		return
+	}
+	for za0003 := range z.Fields {
+		err = en.WriteString(z.Fields[za0003])
+		if err != nil {
+			err = msgp.WrapError(err, "Fields", za0003)
+			return
+		}
+	}
+	// write "backfill"
+	err = en.Append(0xa8, 0x62, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c, 0x6c)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint32(z.Backfill)
+	if err != nil {
+		err = msgp.WrapError(err, "Backfill")
+		return
+	}
+	// write "since"
+	err = en.Append(0xa5, 0x73, 0x69, 0x6e, 0x63, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.Since)
+	if err != nil {
+		err = msgp.WrapError(err, "Since")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgSubscribeRoom) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 6
+	// string "room"
+	o = append(o, 0x86, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
+	o = msgp.AppendInt(o, z.RoomID)
+	// string "cmds"
+	o = append(o, 0xa4, 0x63, 0x6d, 0x64, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Cmds)))
+	for za0001 := range z.Cmds {
+		o = msgp.AppendString(o, z.Cmds[za0001])
+	}
+	// string "filters"
+	o = append(o, 0xa7, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Filters)))
+	for za0002 := range z.Filters {
+		o, err = z.Filters[za0002].MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "Filters", za0002)
+			return
+		}
+	}
+	// string "fields"
+	o = append(o, 0xa6, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Fields)))
+	for za0003 := range z.Fields {
+		o = msgp.AppendString(o, z.Fields[za0003])
+	}
+	// string "backfill"
+	o = append(o, 0xa8, 0x62, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c, 0x6c)
+	o = msgp.AppendUint32(o, z.Backfill)
+	// string "since"
+	o = append(o, 0xa5, 0x73, 0x69, 0x6e, 0x63, 0x65)
+	o = msgp.AppendInt64(o, z.Since)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgSubscribeRoom) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "room":
+			z.RoomID, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "RoomID")
+				return
+			}
+		case "cmds":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Cmds")
+				return
+			}
+			if cap(z.Cmds) >= int(zb0002) {
+				z.Cmds = (z.Cmds)[:zb0002]
+			} else {
+				z.Cmds = make([]string, zb0002)
+			}
+			for za0001 := range z.Cmds {
+				z.Cmds[za0001], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Cmds", za0001)
+					return
+				}
+			}
+		case "filters":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Filters")
+				return
+			}
+			if cap(z.Filters) >= int(zb0003) {
+				z.Filters = (z.Filters)[:zb0003]
+			} else {
+				z.Filters = make([]MsgFilter, zb0003)
+			}
+			for za0002 := range z.Filters {
+				bts, err = z.Filters[za0002].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Filters", za0002)
+					return
+				}
+			}
+		case "fields":
+			var zb0004 uint32
+			zb0004, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Fields")
+				return
+			}
+			if cap(z.Fields) >= int(zb0004) {
+				z.Fields = (z.Fields)[:zb0004]
+			} else {
+				z.Fields = make([]string, zb0004)
+			}
+			for za0003 := range z.Fields {
+				z.Fields[za0003], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Fields", za0003)
+					return
+				}
+			}
+		case "backfill":
+			z.Backfill, bts, err = msgp.ReadUint32Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Backfill")
+				return
+			}
+		case "since":
+			z.Since, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Since")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgSubscribeRoom) Msgsize() (s int) {
+	s = 1 + 5 + msgp.IntSize + 5 + msgp.ArrayHeaderSize
+	for za0001 := range z.Cmds {
+		s += msgp.StringPrefixSize + len(z.Cmds[za0001])
+	}
+	s += 8 + msgp.ArrayHeaderSize
+	for za0002 := range z.Filters {
+		s += z.Filters[za0002].Msgsize()
+	}
+	s += 7 + msgp.ArrayHeaderSize
+	for za0003 := range z.Fields {
+		s += msgp.StringPrefixSize + len(z.Fields[za0003])
+	}
+	s += 9 + msgp.Uint32Size + 6 + msgp.Int64Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgSubscribeRsp) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ok":
+			z.Ok, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "Ok")
+				return
+			}
+		case "msg":
+			z.Msg, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Msg")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z MsgSubscribeRsp) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 2
+	// write "ok"
+	err = en.Append(0x82, 0xa2, 0x6f, 0x6b)
+	if err != nil {
+		return
+	}
+	err = en.WriteBool(z.Ok)
+	if err != nil {
+		err = msgp.WrapError(err, "Ok")
+		return
+	}
+	// write "msg"
+	err = en.Append(0xa3, 0x6d, 0x73, 0x67)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Msg)
+	if err != nil {
+		err = msgp.WrapError(err, "Msg")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z MsgSubscribeRsp) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 2
+	// string "ok"
+	o = append(o, 0x82, 0xa2, 0x6f, 0x6b)
+	o = msgp.AppendBool(o, z.Ok)
+	// string "msg"
+	o = append(o, 0xa3, 0x6d, 0x73, 0x67)
+	o = msgp.AppendString(o, z.Msg)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgSubscribeRsp) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ok":
+			z.Ok, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Ok")
+				return
+			}
+		case "msg":
+			z.Msg, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Msg")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z MsgSubscribeRsp) Msgsize() (s int) {
+	s = 1 + 3 + msgp.BoolSize + 4 + msgp.StringPrefixSize + len(z.Msg)
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *MsgSubscription) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "room":
+			z.RoomID, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "RoomID")
+				return
+			}
+		case "cmds":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Cmds")
+				return
+			}
+			if cap(z.Cmds) >= int(zb0002) {
+				z.Cmds = (z.Cmds)[:zb0002]
+			} else {
+				z.Cmds = make([]string, zb0002)
+			}
+			for za0001 := range z.Cmds {
+				z.Cmds[za0001], err = dc.ReadString()
+				if err != nil {
+					err = msgp.WrapError(err, "Cmds", za0001)
+					return
+				}
+			}
+		case "filters":
+			var zb0003 uint32
+			zb0003, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Filters")
+				return
+			}
+			if cap(z.Filters) >= int(zb0003) {
+				z.Filters = (z.Filters)[:zb0003]
+			} else {
+				z.Filters = make([]MsgFilter, zb0003)
+			}
+			for za0002 := range z.Filters {
+				err = z.Filters[za0002].DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Filters", za0002)
+					return
+				}
+			}
+		case "fields":
+			var zb0004 uint32
+			zb0004, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Fields")
+				return
+			}
+			if cap(z.Fields) >= int(zb0004) {
+				z.Fields = (z.Fields)[:zb0004]
+			} else {
+				z.Fields = make([]string, zb0004)
+			}
+			for za0003 := range z.Fields {
+				z.Fields[za0003], err = dc.ReadString()
+				if err != nil {
+					err = msgp.WrapError(err, "Fields", za0003)
+					return
+				}
+			}
+		case "state":
+			z.State, err = dc.ReadByte()
+			if err != nil {
+				err = msgp.WrapError(err, "State")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *MsgSubscription) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 5
+	// write "room"
+	err = en.Append(0x85, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.RoomID)
+	if err != nil {
+		err = msgp.WrapError(err, "RoomID")
+		return
+	}
+	// write "cmds"
+	err = en.Append(0xa4, 0x63, 0x6d, 0x64, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Cmds)))
+	if err != nil {
+		err = msgp.WrapError(err, "Cmds")
+		return
+	}
+	for za0001 := range z.Cmds {
+		err = en.WriteString(z.Cmds[za0001])
+		if err != nil {
+			err = msgp.WrapError(err, "Cmds", za0001)
+			return
+		}
+	}
+	// write "filters"
+	err = en.Append(0xa7, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Filters)))
+	if err != nil {
+		err = msgp.WrapError(err, "Filters")
+		return
+	}
+	for za0002 := range z.Filters {
+		err = z.Filters[za0002].EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "Filters", za0002)
+			return
+		}
+	}
+	// write "fields"
+	err = en.Append(0xa6, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Fields)))
+	if err != nil {
+		err = msgp.WrapError(err, "Fields")
+		return
+	}
+	for za0003 := range z.Fields {
+		err = en.WriteString(z.Fields[za0003])
+		if err != nil {
+			err = msgp.WrapError(err, "Fields", za0003)
+			return
+		}
+	}
+	// write "state"
+	err = en.Append(0xa5, 0x73, 0x74, 0x61, 0x74, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteByte(z.State)
+	if err != nil {
+		err = msgp.WrapError(err, "State")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *MsgSubscription) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 5
+	// string "room"
+	o = append(o, 0x85, 0xa4, 0x72, 0x6f, 0x6f, 0x6d)
+	o = msgp.AppendInt(o, z.RoomID)
+	// string "cmds"
+	o = append(o, 0xa4, 0x63, 0x6d, 0x64, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Cmds)))
+	for za0001 := range z.Cmds {
+		o = msgp.AppendString(o, z.Cmds[za0001])
+	}
+	// string "filters"
+	o = append(o, 0xa7, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Filters)))
+	for za0002 := range z.Filters {
+		o, err = z.Filters[za0002].MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "Filters", za0002)
+			return
+		}
+	}
+	// string "fields"
+	o = append(o, 0xa6, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Fields)))
+	for za0003 := range z.Fields {
+		o = msgp.AppendString(o, z.Fields[za0003])
+	}
+	// string "state"
+	o = append(o, 0xa5, 0x73, 0x74, 0x61, 0x74, 0x65)
+	o = msgp.AppendByte(o, z.State)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *MsgSubscription) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "room":
+			z.RoomID, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "RoomID")
+				return
+			}
+		case "cmds":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Cmds")
+				return
+			}
+			if cap(z.Cmds) >= int(zb0002) {
+				z.Cmds = (z.Cmds)[:zb0002]
+			} else {
+				z.Cmds = make([]string, zb0002)
+			}
+			for za0001 := range z.Cmds {
+				z.Cmds[za0001], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Cmds", za0001)
+					return
+				}
+			}
+		case "filters":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Filters")
+				return
+			}
+			if cap(z.Filters) >= int(zb0003) {
+				z.Filters = (z.Filters)[:zb0003]
+			} else {
+				z.Filters = make([]MsgFilter, zb0003)
+			}
+			for za0002 := range z.Filters {
+				bts, err = z.Filters[za0002].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Filters", za0002)
+					return
+				}
+			}
+		case "fields":
+			var zb0004 uint32
+			zb0004, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Fields")
+				return
+			}
+			if cap(z.Fields) >= int(zb0004) {
+				z.Fields = (z.Fields)[:zb0004]
+			} else {
+				z.Fields = make([]string, zb0004)
+			}
+			for za0003 := range z.Fields {
+				z.Fields[za0003], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Fields", za0003)
+					return
+				}
+			}
+		case "state":
+			z.State, bts, err = msgp.ReadByteBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "State")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *MsgSubscription) Msgsize() (s int) {
+	s = 1 + 5 + msgp.IntSize + 5 + msgp.ArrayHeaderSize
+	for za0001 := range z.Cmds {
+		s += msgp.StringPrefixSize + len(z.Cmds[za0001])
+	}
+	s += 8 + msgp.ArrayHeaderSize
+	for za0002 := range z.Filters {
+		s += z.Filters[za0002].Msgsize()
+	}
+	s += 7 + msgp.ArrayHeaderSize
+	for za0003 := range z.Fields {
+		s += msgp.StringPrefixSize + len(z.Fields[za0003])
+	}
+	s += 6 + msgp.ByteSize
+	return
+}
@@ -0,0 +1,395 @@
+package client
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zerozwt/toyframe"
+	"github.com/zerozwt/toyframe/dialer"
+)
+
+const (
+	SESSION_STATE_CONNECTING   = 0
+	SESSION_STATE_CONNECTED    = 1
+	SESSION_STATE_DISCONNECTED = 2 // login stream broken or connecting failed, retry after backoff
+	SESSION_STATE_CLOSED       = 3
+)
+
+const (
+	defaultSessionMinBackoff = time.Second
+	defaultSessionMaxBackoff = 30 * time.Second
+)
+
+var ErrSessionClosed = errors.New("session closed")
+
+// SessionEvent reports connection state changes of a Session. Err is the reason of
+// disconnection, or a *SeqGapError with State SESSION_STATE_CONNECTED if some batches were lost.
+type SessionEvent struct {
+	State   int
+	Err     error
+	Resumed bool // login stream resumed with server side subscriptions and cached msgs kept
+}
+
+// Session is a managed client which keeps the login stream alive. It remembers the desired
+// room set and re-subscribes after reconnecting, msgs and state events are surfaced through
+// channels returned by Messages and Events.
+type Session struct {
+	lock     sync.Mutex // guards state below, never held during RPCs
+	rpc_lock sync.Mutex // serializes login, resume and subscribe RPCs
+	client   *Client
+
+	rooms     map[int]MsgSubscribeRoom // desired room set
+	version   uint64                   // incremented on each change of rooms
+	dirty     bool                     // rooms not subscribed yet
+	connected bool
+	started   bool
+	ctx       *toyframe.Context // current login stream
+
+	min_backoff time.Duration
+	max_backoff time.Duration
+
+	msgs     chan MsgSubscribeData
+	events   chan SessionEvent
+	close_ch chan struct{}
+	done_ch  chan struct{}
+	once     sync.Once
+}
+
+func NewSession(name, network, address string, dial dialer.DialFunc) *Session {
+	ret, _ := NewSessionWithEndpoints(name, []Endpoint{{Network: network, Address: address}}, dial)
+	return ret
+}
+
+// NewSessionWithEndpoints creates a session failing over among relay servers, see
+// NewBRelayClientWithEndpoints.
+func NewSessionWithEndpoints(name string, endpoints []Endpoint, dial dialer.DialFunc) (*Session, error) {
+	close_ch := make(chan struct{})
+	relay_client, err := NewBRelayClientWithEndpoints(name, endpoints, dial, close_ch)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		client:      relay_client,
+		rooms:       make(map[int]MsgSubscribeRoom),
+		min_backoff: defaultSessionMinBackoff,
+		max_backoff: defaultSessionMaxBackoff,
+		msgs:        make(chan MsgSubscribeData, 1024),
+		events:      make(chan SessionEvent, 16),
+		close_ch:    close_ch,
+		done_ch:     make(chan struct{}),
+	}, nil
+}
+
+// Client returns the underlying client, options like SetAckMode should be set before Start.
+// In ack mode batches are acked once their msgs are put into the Messages channel.
+func (s *Session) Client() *Client {
+	return s.client
+}
+
+// SetBackoff sets the range of waiting time before reconnecting, the waiting time doubles on
+// each failure. It should be called before Start.
+func (s *Session) SetBackoff(min, max time.Duration) {
+	s.min_backoff, s.max_backoff = min, max
+}
+
+// Start connects to the relay server in background, it does nothing after the session is
+// started or closed.
+func (s *Session) Start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.started || s.closed() {
+		return
+	}
+	s.started = true
+	go s.run()
+}
+
+// Messages returns the channel of received msgs, it is closed after the session is closed.
+func (s *Session) Messages() <-chan MsgSubscribeData {
+	return s.msgs
+}
+
+// Events returns the channel of state events, it is closed after the session is closed.
+// Events are dropped if not consumed in time.
+func (s *Session) Events() <-chan SessionEvent {
+	return s.events
+}
+
+// Connected reports whether the login stream is currently alive.
+func (s *Session) Connected() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.connected
+}
+
+// Subscribe replaces the desired room set with rooms. If the session is connected the
+// subscription is applied immediately, otherwise or if applying failed it is applied after
+// (re)connected.
+func (s *Session) Subscribe(rooms []MsgSubscribeRoom) error {
+	return s.update(func(tmp map[int]MsgSubscribeRoom) {
+		for room_id := range tmp {
+			delete(tmp, room_id)
+		}
+		for _, item := range rooms {
+			tmp[item.RoomID] = item
+		}
+	})
+}
+
+// AddRooms adds rooms to the desired room set, replacing existing entries of same rooms.
+func (s *Session) AddRooms(rooms ...MsgSubscribeRoom) error {
+	return s.update(func(tmp map[int]MsgSubscribeRoom) {
+		for _, item := range rooms {
+			tmp[item.RoomID] = item
+		}
+	})
+}
+
+// RemoveRooms removes rooms from the desired room set.
+func (s *Session) RemoveRooms(room_ids ...int) error {
+	return s.update(func(tmp map[int]MsgSubscribeRoom) {
+		for _, room_id := range room_ids {
+			delete(tmp, room_id)
+		}
+	})
+}
+
+// Rooms returns the desired room set ordered by room id.
+func (s *Session) Rooms() []MsgSubscribeRoom {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return roomList(s.rooms)
+}
+
+// Close stops reconnecting and logs out if connected, channels of the session are closed
+// after it returns.
+func (s *Session) Close() error {
+	first := false
+	s.once.Do(func() {
+		first = true
+		close(s.close_ch)
+		s.lock.Lock()
+		if s.ctx != nil {
+			s.ctx.Close()
+		}
+		started := s.started
+		s.lock.Unlock()
+		if !started {
+			s.finish() // no run goroutine to close channels
+		}
+	})
+	if !first {
+		<-s.done_ch
+		return ErrSessionClosed
+	}
+	<-s.done_ch
+
+	s.rpc_lock.Lock()
+	defer s.rpc_lock.Unlock()
+	s.lock.Lock()
+	connected := s.connected
+	s.connected = false
+	s.lock.Unlock()
+	if !connected {
+		return nil
+	}
+	s.client.ich = nil // interruptor is closed, logout without it
+	return s.client.Logout()
+}
+
+// update applies change to a copy of the desired room set, and subscribes the result if
+// connected.
+func (s *Session) update(change func(rooms map[int]MsgSubscribeRoom)) error {
+	s.lock.Lock()
+	if s.closed() {
+		s.lock.Unlock()
+		return ErrSessionClosed
+	}
+	rooms := s.copyRooms()
+	change(rooms)
+	s.rooms = rooms
+	s.version++
+	s.dirty = true
+	connected := s.connected
+	s.lock.Unlock()
+
+	if !connected {
+		return nil // subscribed by connect
+	}
+	s.rpc_lock.Lock()
+	defer s.rpc_lock.Unlock()
+	return s.subscribeDirty()
+}
+
+// subscribeDirty subscribes the desired room set if not subscribed yet, it is called with
+// rpc_lock held. Changes made during the RPC keep the room set dirty for their own update.
+func (s *Session) subscribeDirty() error {
+	s.lock.Lock()
+	if !s.dirty {
+		s.lock.Unlock()
+		return nil
+	}
+	rooms, version := roomList(s.rooms), s.version
+	s.lock.Unlock()
+
+	err := s.client.Subscribe(rooms)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err == nil && s.version == version {
+		s.dirty = false
+	}
+	return err
+}
+
+func (s *Session) copyRooms() map[int]MsgSubscribeRoom {
+	ret := make(map[int]MsgSubscribeRoom, len(s.rooms))
+	for room_id, item := range s.rooms {
+		ret[room_id] = item
+	}
+	return ret
+}
+
+func roomList(rooms map[int]MsgSubscribeRoom) []MsgSubscribeRoom {
+	ret := make([]MsgSubscribeRoom, 0, len(rooms))
+	for _, item := range rooms {
+		ret = append(ret, item)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].RoomID < ret[j].RoomID })
+	return ret
+}
+
+func (s *Session) run() {
+	defer s.finish()
+
+	logged_in := false
+	wait_time := s.min_backoff
+
+	for {
+		s.emit(SessionEvent{State: SESSION_STATE_CONNECTING})
+		ctx, resumed, err := s.connect(logged_in)
+		if s.closed() {
+			if ctx != nil {
+				ctx.Close()
+			}
+			return
+		}
+
+		if err == nil {
+			logged_in = true
+			wait_time = s.min_backoff
+			s.emit(SessionEvent{State: SESSION_STATE_CONNECTED, Resumed: resumed})
+			err = s.readLoop(ctx)
+			ctx.Close()
+			if s.closed() {
+				return // keep connected for Close to logout
+			}
+
+			s.lock.Lock()
+			s.ctx = nil
+			s.connected = false
+			s.lock.Unlock()
+		}
+
+		s.emit(SessionEvent{State: SESSION_STATE_DISCONNECTED, Err: err})
+		select {
+		case <-time.After(wait_time):
+		case <-s.close_ch:
+			return
+		}
+		wait_time *= 2
+		if wait_time > s.max_backoff {
+			wait_time = s.max_backoff
+		}
+	}
+}
+
+// connect resumes the last subscriber if possible, otherwise it logs in again and
+// subscribes the desired room set.
+func (s *Session) connect(logged_in bool) (*toyframe.Context, bool, error) {
+	s.rpc_lock.Lock()
+	defer s.rpc_lock.Unlock()
+
+	var ctx *toyframe.Context
+	var err error
+	resumed := false
+	if logged_in {
+		ctx, err = s.client.Resume()
+		resumed = err == nil
+	}
+	if !resumed {
+		// rooms are subscribed below, do not let Login re-apply the last subscriptions
+		s.client.endpoints.setSubscriptions(nil)
+		if ctx, err = s.client.Login(); err != nil {
+			return nil, false, err
+		}
+		s.lock.Lock()
+		s.dirty = len(s.rooms) > 0 // the new subscriber has no subscriptions
+		s.lock.Unlock()
+	}
+
+	// rooms changed before connected are not subscribed by update, so repeat until clean
+	for {
+		if err = s.subscribeDirty(); err != nil {
+			ctx.Close()
+			return nil, false, err
+		}
+		s.lock.Lock()
+		if !s.dirty {
+			s.ctx, s.connected = ctx, true
+			s.lock.Unlock()
+			return ctx, resumed, nil
+		}
+		s.lock.Unlock()
+	}
+}
+
+func (s *Session) readLoop(ctx *toyframe.Context) error {
+	for {
+		msgs, gap, err := s.client.readMessages(ctx)
+		if err != nil {
+			return err
+		}
+		if gap != nil {
+			s.emit(SessionEvent{State: SESSION_STATE_CONNECTED, Err: gap})
+		}
+
+		for _, msg := range msgs {
+			select {
+			case s.msgs <- msg:
+			case <-s.close_ch:
+				return ErrSessionClosed
+			}
+		}
+
+		if s.client.ack && len(msgs) > 0 {
+			if err := s.client.Ack(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Session) finish() {
+	s.emit(SessionEvent{State: SESSION_STATE_CLOSED})
+	close(s.msgs)
+	close(s.events)
+	close(s.done_ch)
+}
+
+func (s *Session) emit(event SessionEvent) {
+	select {
+	case s.events <- event:
+	default:
+	}
+}
+
+func (s *Session) closed() bool {
+	select {
+	case <-s.close_ch:
+		return true
+	default:
+		return false
+	}
+}
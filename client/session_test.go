@@ -0,0 +1,51 @@
+package client
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSessionNotBlockedByLogin(t *testing.T) {
+	dialing, release := make(chan struct{}, 1), make(chan struct{})
+	dial := func(network, addr string) (net.Conn, error) {
+		select {
+		case dialing <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil, errors.New("connection refused")
+	}
+
+	s := NewSession("test", "tcp", "localhost:0", dial)
+	s.Start()
+	defer s.Close()
+	defer close(release)
+
+	select {
+	case <-dialing:
+	case <-time.After(time.Second):
+		t.Fatal("session did not start login")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		if s.Connected() {
+			done <- errors.New("connected while login is pending")
+			return
+		}
+		done <- s.AddRooms(MsgSubscribeRoom{RoomID: 1})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("session state blocked by pending login")
+	}
+	if rooms := s.Rooms(); len(rooms) != 1 || rooms[0].RoomID != 1 {
+		t.Errorf("unexpected rooms %+v", rooms)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
+	"github.com/zerozwt/brelay/client"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,17 +16,117 @@ type ServerConfig struct {
 	Inbounds []InboundConfig `yaml:"inbounds"`
 	LogFile  string          `yaml:"log_file"`
 	LoginKey string          `yaml:"login_key"`
+	AdminKey string          `yaml:"admin_key"` // key of admin requests, empty to disable admin requests
+
+	RoomIdleTimeout time.Duration `yaml:"room_idle_timeout"` // close upstream connection after room has no subscribers for this long
+	ResumeTimeout   time.Duration `yaml:"resume_timeout"`    // keep subscriber of a broken login stream for resume, 0 to disable
+
+	CacheLimit CacheLimitConfig `yaml:"cache_limit"`
+
+	FlushInterval time.Duration `yaml:"flush_interval"`  // default interval of sending cached msgs to subscribers
+	MaxBatchMsgs  int           `yaml:"max_batch_msgs"`  // max msgs in a single batch sent to subscribers
+	MaxBatchBytes int           `yaml:"max_batch_bytes"` // max bytes of msgs in a single batch sent to subscribers
+
+	HistorySize int `yaml:"history_size"` // recent msgs kept per cmd of each room for backfill, 0 to disable
+
+	Recorder RecorderConfig `yaml:"recorder"`
+	Replays  []ReplayConfig `yaml:"replays"`
+
+	Metrics MetricsConfig `yaml:"metrics"`
+
+	Webhooks   []WebhookConfig   `yaml:"webhooks"`
+	RedisSinks []RedisSinkConfig `yaml:"redis_sinks"`
+}
+
+type MetricsConfig struct {
+	Addr string `yaml:"addr"` // http address of prometheus metrics, empty to disable
+	Path string `yaml:"path"` // default /metrics
+}
+
+// SinkRoomConfig is a room subscribed by a sink, all cmds are subscribed if cmds is empty
+type SinkRoomConfig struct {
+	RoomID  int                `yaml:"room_id"`
+	Cmds    []string           `yaml:"cmds"`
+	Filters []client.MsgFilter `yaml:"filters"`
+	Fields  []string           `yaml:"fields"`
+}
+
+type WebhookConfig struct {
+	Name  string           `yaml:"name"`
+	URL   string           `yaml:"url"`
+	Rooms []SinkRoomConfig `yaml:"rooms"`
+
+	Secret        string        `yaml:"secret"`         // key of HMAC-SHA256 signature, empty for no signature
+	FlushInterval time.Duration `yaml:"flush_interval"` // interval of posting batches, 0 for default
+	Timeout       time.Duration `yaml:"timeout"`        // timeout of a single post
+
+	SinkRetryConfig `yaml:",inline"`
+}
+
+type RedisSinkConfig struct {
+	Name     string           `yaml:"name"`
+	Addr     string           `yaml:"addr"`
+	Username string           `yaml:"username"` // empty for AUTH with password only
+	Password string           `yaml:"password"` // empty for no AUTH
+	Prefix   string           `yaml:"prefix"`   // channel is PREFIX:ROOM_ID:CMD, default brelay
+	Rooms    []SinkRoomConfig `yaml:"rooms"`
+
+	FlushInterval time.Duration `yaml:"flush_interval"` // interval of publishing batches, 0 for default
+	Timeout       time.Duration `yaml:"timeout"`        // timeout of dialing and publishing a batch
+
+	SinkRetryConfig `yaml:",inline"`
+}
+
+type SinkRetryConfig struct {
+	MaxRetries       int           `yaml:"max_retries"`        // retries before giving up a batch, negative for unlimited
+	RetryInterval    time.Duration `yaml:"retry_interval"`     // wait before first retry, doubled on each retry
+	MaxRetryInterval time.Duration `yaml:"max_retry_interval"` // max wait between retries
+	DeadLetterFile   string        `yaml:"dead_letter_file"`   // batches failed to be delivered are appended to it
+}
+
+type RecorderConfig struct {
+	Dir         string `yaml:"dir"`           // directory of record files, empty to disable recording
+	Rooms       []int  `yaml:"rooms"`         // rooms to be recorded, connected even without subscribers
+	All         bool   `yaml:"all"`           // record all subscribed rooms
+	Compress    bool   `yaml:"compress"`      // gzip record files
+	MaxFileSize int64  `yaml:"max_file_size"` // rotate record file of the same day when exceeded, 0 for no limit
+}
+
+type ReplayConfig struct {
+	RoomID int           `yaml:"room_id"` // virtual room id, should not conflict with real rooms
+	Title  string        `yaml:"title"`
+	Files  []string      `yaml:"files"`   // record files or glob patterns, played in order
+	Speed  float64       `yaml:"speed"`   // playback speed, 1 for original pace
+	Loop   bool          `yaml:"loop"`    // replay from the first file after all files are played
+	MaxGap time.Duration `yaml:"max_gap"` // max wait between two msgs, 0 for no limit
+}
+
+type CacheLimitConfig struct {
+	MaxMsgs  int    `yaml:"max_msgs"`  // max cached msgs per subscriber
+	MaxBytes int    `yaml:"max_bytes"` // max cached bytes per subscriber
+	Policy   string `yaml:"policy"`    // drop_oldest, drop_newest, disconnect or coalesce
 }
 
 type InboundConfig struct {
 	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"` // one of INBOUND_TYPE_*, empty for toyframe
 	Addr        string   `yaml:"addr"`
 	Filters     []string `yaml:"filters"`
 	TlsKeyFile  string   `yaml:"tls_key"`
 	TlsPEMFile  string   `yaml:"tls_pem"`
 	TlsInsecure bool     `yaml:"tls_insecure"`
+
+	Path         string   `yaml:"path"`          // http path of websocket inbound, default /ws
+	AllowOrigins []string `yaml:"allow_origins"` // allowed origins of websocket and http inbound, empty for all
+	Tokens       []string `yaml:"tokens"`        // access tokens of http inbound besides configured login key
 }
 
+const (
+	INBOUND_TYPE_TOYFRAME  = "toyframe"
+	INBOUND_TYPE_WEBSOCKET = "websocket"
+	INBOUND_TYPE_HTTP      = "http" // server-sent events of rooms
+)
+
 var gConf ServerConfig
 
 func initConf() bool {
@@ -12,86 +12,343 @@ import (
 
 type dmClientManager struct {
 	sync.Mutex
-	clients map[int]*dmClient
+	clients  map[int]*dmClient
+	pinned   map[int]struct{} // rooms kept connected without subscribers
+	upstream upstreamDialer
+
+	// jobs posted by dmManager are run in order by a single goroutine, so that adding and
+	// releasing of a room are not reordered
+	job_lock   sync.Mutex
+	jobs       []func()
+	job_signal chan struct{}
 }
 
 const (
-	DM_CLIENT_STATE_INIT       = 0
-	DM_CLIENT_STATE_CONNECTING = 1
-	DM_CLIENT_STATE_CONNECTED  = 2
+	DM_CLIENT_STATE_INIT       = client.ROOM_STATE_INIT
+	DM_CLIENT_STATE_CONNECTING = client.ROOM_STATE_CONNECTING
+	DM_CLIENT_STATE_CONNECTED  = client.ROOM_STATE_CONNECTED
 )
 
+const defaultRoomIdleTimeout = 30 * time.Second
+const pinnedRoomRetryInterval = 30 * time.Second
+
 type dmClient struct {
-	client *dm.Client
-	state  int
+	client  upstreamConn
+	state   int
+	dropped upstreamConn // connection disconnected while dialing, before onDialResult
+
+	idle     *time.Timer   // non-nil while the room has no subscribers
+	idle_gen int           // identifies the latest idle timer
+	close_ch chan struct{} // closed when the room is released
 }
 
-var gClientMgr *dmClientManager = &dmClientManager{
-	clients: make(map[int]*dmClient),
+var gClientMgr *dmClientManager = newDMClientManager()
+
+func newDMClientManager() *dmClientManager {
+	ret := &dmClientManager{
+		clients:    make(map[int]*dmClient),
+		pinned:     make(map[int]struct{}),
+		upstream:   dialBLive,
+		job_signal: make(chan struct{}, 1),
+	}
+	go ret.runJobs()
+	return ret
+}
+
+// PostJob runs job after previously posted ones without blocking the caller
+func (m *dmClientManager) PostJob(job func()) {
+	m.job_lock.Lock()
+	m.jobs = append(m.jobs, job)
+	m.job_lock.Unlock()
+	select {
+	case m.job_signal <- struct{}{}:
+	default:
+	}
+}
+
+func (m *dmClientManager) runJobs() {
+	for {
+		select {
+		case <-m.job_signal:
+		case <-gServer.CloseChannel():
+			return
+		}
+		m.job_lock.Lock()
+		jobs := m.jobs
+		m.jobs = nil
+		m.job_lock.Unlock()
+		for _, job := range jobs {
+			job()
+		}
+	}
 }
 
 func (m *dmClientManager) AddClient(sub_id uint32, room_id int) {
 	m.Lock()
 	defer m.Unlock()
+	m.addClient(room_id, []uint32{sub_id})
+}
 
+// PinClient keeps the connection of a room alive regardless of subscribers, dialing is
+// retried if failed.
+func (m *dmClientManager) PinClient(room_id int) {
+	m.Lock()
+	defer m.Unlock()
+	m.pinned[room_id] = struct{}{}
+	m.addClient(room_id, nil)
+}
+
+func (m *dmClientManager) addClient(room_id int, notify []uint32) {
 	if info, ok := m.clients[room_id]; ok {
-		if info.state == DM_CLIENT_STATE_CONNECTED {
-			gDanmaku.UpdateRommState(room_id, client.MSG_TYPE_WS_CONNECT, info.client.Room(), []uint32{sub_id})
+		if info.idle != nil {
+			info.idle.Stop()
+			info.idle = nil
+		}
+		if info.state == DM_CLIENT_STATE_CONNECTED && len(notify) > 0 {
+			gDanmaku.UpdateRommState(room_id, client.MSG_TYPE_WS_CONNECT, info.client.Room(), notify)
 		}
 		return
 	}
 
 	// mark as connecting
-	m.clients[room_id] = &dmClient{
-		client: nil,
-		state:  DM_CLIENT_STATE_CONNECTING,
+	info := &dmClient{
+		client:   nil,
+		state:    DM_CLIENT_STATE_CONNECTING,
+		close_ch: make(chan struct{}),
 	}
+	m.clients[room_id] = info
 
 	// async dial
 	go func() {
-		tmp, err := dm.Dial(room_id, m.dmConfig(room_id))
-		m.onDialResult(room_id, tmp, err)
+		tmp, err := m.dial(room_id, info.close_ch)
+		if err == toyframe.ErrInterrupted {
+			return
+		}
+		m.onDialResult(room_id, info, tmp, err)
 	}()
 }
 
-func (m *dmClientManager) onDialResult(room_id int, dm_client *dm.Client, err error) {
+// AdminRooms returns upstream connections of all rooms, Subscribers is not filled
+func (m *dmClientManager) AdminRooms() []client.MsgAdminRoom {
+	m.Lock()
+	defer m.Unlock()
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	ret := make([]client.MsgAdminRoom, 0, len(m.clients))
+	for room_id, info := range m.clients {
+		_, pinned := m.pinned[room_id]
+		item := client.MsgAdminRoom{RoomID: room_id, State: byte(info.state), Pinned: pinned, Idle: info.idle != nil}
+		if info.client != nil {
+			item.Info, _ = json.Marshal(info.client.Room())
+		}
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+// ReconnectRoom closes the upstream connection of a room and reconnects it, it returns false
+// if the room is not connected.
+func (m *dmClientManager) ReconnectRoom(room_id int) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	info, ok := m.clients[room_id]
+	if !ok || info.client == nil {
+		return false
+	}
+	logger().Printf("reconnect live room %d by admin", room_id)
+	gDanmaku.UpdateRommState(room_id, client.MSG_TYPE_WS_DISCONNECT, info.client.Room(), nil)
+	info.client.Close()
+	info.client = nil
+	info.state = DM_CLIENT_STATE_CONNECTING
+	m.reconnect(room_id, info)
+	return true
+}
+
+// CloseRoom closes the upstream connection of a room regardless of subscribers and pinning,
+// it returns false if the room is not connected or connecting.
+func (m *dmClientManager) CloseRoom(room_id int) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.pinned, room_id)
+	info, ok := m.clients[room_id]
+	if !ok {
+		return false
+	}
+	logger().Printf("close live room %d by admin", room_id)
+	delete(m.clients, room_id)
+	close(info.close_ch)
+	if info.idle != nil {
+		info.idle.Stop()
+	}
+	if info.client != nil {
+		info.client.Close()
+	}
+	return true
+}
+
+// StateCounts returns number of rooms by state
+func (m *dmClientManager) StateCounts() map[int]int {
+	m.Lock()
+	defer m.Unlock()
+	ret := make(map[int]int)
+	for _, info := range m.clients {
+		ret[info.state]++
+	}
+	return ret
+}
+
+func (m *dmClientManager) RoomState(room_id int) int {
+	m.Lock()
+	defer m.Unlock()
+	if info, ok := m.clients[room_id]; ok {
+		return info.state
+	}
+	return DM_CLIENT_STATE_INIT
+}
+
+// ReleaseClient is called when the last subscriber of a room has gone. The upstream
+// connection is kept for an idle grace period, after which it is closed if the room
+// still has no subscribers.
+func (m *dmClientManager) ReleaseClient(room_id int) {
 	m.Lock()
 	defer m.Unlock()
 
+	info, ok := m.clients[room_id]
+	if !ok || info.idle != nil {
+		return
+	}
+	if _, ok := m.pinned[room_id]; ok {
+		return
+	}
+
+	timeout := gConf.RoomIdleTimeout
+	if timeout <= 0 {
+		timeout = defaultRoomIdleTimeout
+	}
+	info.idle_gen++
+	gen := info.idle_gen
+	info.idle = time.AfterFunc(timeout, func() { m.onIdleTimeout(room_id, info, gen) })
+}
+
+func (m *dmClientManager) onIdleTimeout(room_id int, info *dmClient, gen int) {
+	// subscriptions may have been added after the timer started
+	count, err := gDanmaku.RoomSubscriberCount(room_id)
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.clients[room_id] != info || info.idle == nil || info.idle_gen != gen {
+		return // room was re-subscribed or replaced in the meantime
+	}
+	info.idle = nil
+	if err != nil || count > 0 {
+		return
+	}
+
+	logger().Printf("live room %d has no subscribers for a while, close connection", room_id)
+	delete(m.clients, room_id)
+	close(info.close_ch)
+	if info.client != nil {
+		info.client.Close()
+	}
+	gDanmaku.ClearRoom(room_id)
+}
+
+// dial connects to a live room, it returns toyframe.ErrInterrupted if the room is released
+// or the server shuts down before dialing finishes. Connections established after that are
+// closed immediately.
+func (m *dmClientManager) dial(room_id int, close_ch chan struct{}) (upstreamConn, error) {
+	type dialResult struct {
+		client upstreamConn
+		err    error
+	}
+	result_ch := make(chan dialResult, 1)
+	countMetric(&gCounters.upstream_dials, 1)
+	go func() {
+		tmp, err := m.upstream(room_id, m.upstreamHandler(room_id))
+		result_ch <- dialResult{client: tmp, err: err}
+	}()
+
+	select {
+	case ret := <-result_ch:
+		return ret.client, ret.err
+	case <-close_ch:
+	case <-gServer.CloseChannel():
+	}
+
+	go func() {
+		if ret := <-result_ch; ret.err == nil {
+			ret.client.Close()
+		}
+	}()
+	return nil, toyframe.ErrInterrupted
+}
+
+func (m *dmClientManager) onDialResult(room_id int, info *dmClient, dm_client upstreamConn, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.clients[room_id] != info {
+		// room released while dialing
+		if err == nil {
+			dm_client.Close()
+		}
+		return
+	}
+
 	if err != nil {
+		countMetric(&gCounters.upstream_dial_failures, 1)
 		delete(m.clients, room_id)
+		close(info.close_ch)
+		if info.idle != nil {
+			info.idle.Stop()
+		}
 		gDanmaku.UpdateRommState(room_id, client.MSG_TYPE_ROOM_CONN_FAIL, nil, nil)
+		if _, ok := m.pinned[room_id]; ok {
+			logger().Printf("connect pinned live room %d failed: %v, retry after %v", room_id, err, pinnedRoomRetryInterval)
+			time.AfterFunc(pinnedRoomRetryInterval, func() {
+				select {
+				case <-gServer.CloseChannel():
+				default:
+					m.PinClient(room_id)
+				}
+			})
+		}
 		return
 	}
 
-	m.clients[room_id] = &dmClient{
-		client: dm_client,
-		state:  DM_CLIENT_STATE_CONNECTED,
+	dropped := info.dropped
+	info.dropped = nil
+	if dropped == dm_client {
+		countMetric(&gCounters.upstream_disconnects, 1)
+		logger().Printf("connection to live_room %d interrupted before established", room_id)
+		dm_client.Close()
+		m.reconnect(room_id, info)
+		return
 	}
+
+	info.client = dm_client
+	info.state = DM_CLIENT_STATE_CONNECTED
 	gDanmaku.UpdateRommState(room_id, client.MSG_TYPE_WS_CONNECT, dm_client.Room(), nil)
 }
 
-func (m *dmClientManager) dmConfig(room_id int) *dm.ClientConf {
-	tmp_disconnect := func(dm_client *dm.Client, err error) {
-		m.onDisconnect(room_id, dm_client, err)
-	}
-	ret := &dm.ClientConf{
-		OnNetError:         tmp_disconnect,
-		OnServerDisconnect: tmp_disconnect,
+func (m *dmClientManager) upstreamHandler(room_id int) *upstreamHandler {
+	return &upstreamHandler{
+		OnMsg: func(_ upstreamConn, data []byte) {
+			m.onRoomMsg(room_id, data)
+		},
+		OnLiveStateChange: func(dm_client upstreamConn, cmd string) {
+			m.onLiveStateChange(dm_client, cmd, room_id)
+		},
+		OnDisconnect: func(dm_client upstreamConn, err error) {
+			m.onDisconnect(room_id, dm_client, err)
+		},
 	}
-	ret.AddOpHandler(dm.OP_SEND_MSG_REPLY, func(_ *dm.Client, msg *dm.RawMessage) bool {
-		return m.onRoomMsg(room_id, msg)
-	})
-	tmp_live_state_change := func(dm_client *dm.Client, cmd string, _ []byte) bool {
-		return m.onLiveStateChange(dm_client, cmd, room_id)
-	}
-	ret.AddCmdHandler(dm.CMD_LIVE, tmp_live_state_change)
-	ret.AddCmdHandler(dm.CMD_PREPARING, tmp_live_state_change)
-	return ret
 }
 
-func (m *dmClientManager) onRoomMsg(room_id int, msg *dm.RawMessage) bool {
-	iter := jsoniter.NewIterator(jsoniter.ConfigCompatibleWithStandardLibrary).ResetBytes(msg.Data)
+func (m *dmClientManager) onRoomMsg(room_id int, data []byte) {
+	iter := jsoniter.NewIterator(jsoniter.ConfigCompatibleWithStandardLibrary).ResetBytes(data)
 	cmd := ""
 	iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
 		if key == "cmd" {
@@ -101,35 +358,45 @@ func (m *dmClientManager) onRoomMsg(room_id int, msg *dm.RawMessage) bool {
 		}
 		return true
 	})
+	gRecorder.Record(room_id, cmd, data)
 	if cmd == dm.CMD_LIVE || cmd == dm.CMD_PREPARING {
-		return false
+		return
 	}
-	gDanmaku.OnRoomMsg(room_id, cmd, msg.Data)
-	return false
+	gDanmaku.OnRoomMsg(room_id, cmd, data)
 }
 
-func (m *dmClientManager) onLiveStateChange(dm_client *dm.Client, cmd string, room_id int) bool {
+func (m *dmClientManager) onLiveStateChange(dm_client upstreamConn, cmd string, room_id int) {
 	json := jsoniter.ConfigCompatibleWithStandardLibrary
 	data, _ := json.Marshal(dm_client.Room())
 	gDanmaku.OnRoomLiveStateChange(room_id, cmd, data)
-	return false
 }
 
-func (m *dmClientManager) onDisconnect(room_id int, dm_client *dm.Client, err error) {
+func (m *dmClientManager) onDisconnect(room_id int, dm_client upstreamConn, err error) {
 	m.Lock()
 	defer m.Unlock()
 
+	info, ok := m.clients[room_id]
+	if ok && info.client == nil && info.state == DM_CLIENT_STATE_CONNECTING {
+		info.dropped = dm_client // still dialing, handled by onDialResult
+		return
+	}
+	if !ok || info.client != dm_client {
+		return // connection already released
+	}
+
 	// notify subscribers
+	countMetric(&gCounters.upstream_disconnects, 1)
 	logger().Printf("coneection to live_room %d interrupted: %v", room_id, err)
 	gDanmaku.UpdateRommState(room_id, client.MSG_TYPE_WS_DISCONNECT, dm_client.Room(), nil)
 
 	// mark as connecting before reconnect
-	m.clients[room_id] = &dmClient{
-		client: nil,
-		state:  DM_CLIENT_STATE_CONNECTING,
-	}
+	info.client = nil
+	info.state = DM_CLIENT_STATE_CONNECTING
+	m.reconnect(room_id, info)
+}
 
-	// try reconnect ...
+// reconnect dials a room with backoff until connected or the room is released
+func (m *dmClientManager) reconnect(room_id int, info *dmClient) {
 	go func() {
 		logger().Printf("try reconnect live room %d ...", room_id)
 
@@ -137,22 +404,28 @@ func (m *dmClientManager) onDisconnect(room_id int, dm_client *dm.Client, err er
 		max_wait_time := 30 * time.Second
 
 		for {
-			err2 := toyframe.DoWithInterruptor(func() {
-				dm_client, err = dm.Dial(room_id, m.dmConfig(room_id))
-			}, gServer.CloseChannel())
+			dm_client, err := m.dial(room_id, info.close_ch)
 
-			if err2 != nil {
-				logger().Printf("reconnect live room %d failed: %v", room_id, err)
+			if err == toyframe.ErrInterrupted {
+				logger().Printf("reconnect live room %d cancelled", room_id)
 				return
 			}
 
 			if err == nil {
-				m.onDialResult(room_id, dm_client, err)
+				m.onDialResult(room_id, info, dm_client, err)
 				return
 			}
 
+			countMetric(&gCounters.upstream_dial_failures, 1)
 			logger().Printf("reconnect live room %d failed: %v, reconnect after %v ...", room_id, err, wait_time)
-			time.Sleep(wait_time)
+			select {
+			case <-time.After(wait_time):
+			case <-info.close_ch:
+				logger().Printf("reconnect live room %d cancelled", room_id)
+				return
+			case <-gServer.CloseChannel():
+				return
+			}
 			wait_time *= 2
 			if wait_time > max_wait_time {
 				wait_time = max_wait_time
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dm "github.com/zerozwt/BLiveDanmaku"
+)
+
+type testUpstreamConn struct {
+	closed int32
+}
+
+func (c *testUpstreamConn) Room() *dm.RoomInfo { return nil }
+func (c *testUpstreamConn) Close()             { atomic.StoreInt32(&c.closed, 1) }
+
+func TestDisconnectWhileDialing(t *testing.T) {
+	var lock sync.Mutex
+	conns := []*testUpstreamConn{}
+	m := &dmClientManager{
+		clients: make(map[int]*dmClient),
+		pinned:  make(map[int]struct{}),
+		upstream: func(room_id int, handler *upstreamHandler) (upstreamConn, error) {
+			conn := &testUpstreamConn{}
+			lock.Lock()
+			conns = append(conns, conn)
+			first := len(conns) == 1
+			lock.Unlock()
+			if first {
+				handler.OnDisconnect(conn, errors.New("connection reset")) // before dial returns
+			}
+			return conn, nil
+		},
+	}
+
+	m.AddClient(0, 2001)
+	deadline := time.Now().Add(time.Second)
+	for m.RoomState(2001) != DM_CLIENT_STATE_CONNECTED && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	lock.Lock()
+	defer lock.Unlock()
+	if len(conns) != 2 {
+		t.Fatalf("room dialed %d times, expect reconnected once", len(conns))
+	}
+	if info := m.clients[2001]; info == nil || info.state != DM_CLIENT_STATE_CONNECTED || info.client != conns[1] {
+		t.Errorf("room not connected with the new connection: %+v", info)
+	}
+	if atomic.LoadInt32(&conns[0].closed) == 0 || atomic.LoadInt32(&conns[1].closed) != 0 {
+		t.Errorf("dropped connection not closed or new connection closed")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	math_rand "math/rand"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -14,28 +15,65 @@ import (
 )
 
 type subscriberInfo struct {
-	id   uint32
-	cmds []string
+	id      uint32
+	cmds    []string
+	matcher *cmdMatcher // compiled from cmds
+	filters []client.MsgFilter
+	filter  []*jsonFilter // compiled from filters
+	fields  []string
+	proj    *projection // compiled from fields, nil for whole data
+
+	backfill int   // recent msgs requested on subscription
+	since    int64 // unix timestamp in milliseconds of earliest msgs requested on subscription
 }
 
-type subMailbox chan client.MsgSubscribeBatch
+func newSubscriberInfo(sub_id uint32, room *client.MsgSubscribeRoom) (*subscriberInfo, error) {
+	filter, err := compileFilters(room.Filters)
+	if err != nil {
+		return nil, err
+	}
+	return &subscriberInfo{
+		id:      sub_id,
+		cmds:    append([]string{}, room.Cmds...),
+		matcher: compileCmdMatcher(room.Cmds),
+		filters: append([]client.MsgFilter{}, room.Filters...),
+		filter:  filter,
+		fields:  append([]string{}, room.Fields...),
+		proj:    compileProjection(room.Fields),
+
+		backfill: int(room.Backfill),
+		since:    room.Since,
+	}, nil
+}
+
+func (info *subscriberInfo) accept(cmd string, data []byte) bool {
+	return info.matcher.Match(cmd) && matchFilters(info.filter, cmd, data)
+}
 
 type dmManager struct {
-	job_ch  chan func()
-	subs    map[int]map[uint32]*subscriberInfo    // room_id => subscriber_id => subscribers
-	cache   map[uint32][]client.MsgSubscribeBatch // subscriber_id => cached msgs
-	mailbox map[uint32]subMailbox                 // subscriber_id => recv channel
-	next_id uint32
+	job_ch      chan func()
+	flush_wake  chan struct{}                      // wakes flushSchedule when a subscriber needs flushing earlier
+	subs        map[int]map[uint32]*subscriberInfo // room_id => subscriber_id => subscribers
+	subscribers map[uint32]*subscriber             // subscriber_id => subscriber
+	history     map[int]*roomHistory               // room_id => recent msgs
+	room_msgs   map[int]map[string]uint64          // room_id => cmd => msgs received
+	next_id     uint32
 }
 
 var gDanmaku *dmManager = newBLiveDanmakuManager()
 
+// limit of distinct cmds counted for a room, so that unknown cmds do not grow metrics unbounded
+const maxRoomMsgCmds = 64
+const roomMsgOtherCmd = "_other"
+
 func newBLiveDanmakuManager() *dmManager {
 	ret := &dmManager{
-		job_ch:  make(chan func(), 1024),
-		subs:    make(map[int]map[uint32]*subscriberInfo),
-		cache:   make(map[uint32][]client.MsgSubscribeBatch),
-		mailbox: make(map[uint32]subMailbox),
+		job_ch:      make(chan func(), 1024),
+		flush_wake:  make(chan struct{}, 1),
+		subs:        make(map[int]map[uint32]*subscriberInfo),
+		subscribers: make(map[uint32]*subscriber),
+		history:     make(map[int]*roomHistory),
+		room_msgs:   make(map[int]map[string]uint64),
 	}
 	if err := binary.Read(rand.Reader, binary.BigEndian, &(ret.next_id)); err != nil {
 		logger().Printf("subscriber id generator from crypto/rand failed: %v, use math/rand instead", err)
@@ -62,8 +100,31 @@ func (m *dmManager) ExecJob(job func()) error {
 }
 
 func (m *dmManager) flushSchedule() {
-	for m.ExecJob(m.flushData) == nil {
-		time.Sleep(time.Second)
+	timer := time.NewTimer(0)
+	for {
+		select {
+		case <-timer.C:
+		case <-m.flush_wake:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		case <-gServer.CloseChannel():
+			return
+		}
+
+		next := time.Time{}
+		if m.ExecJob(func() { next = m.flushData(time.Now()) }) != nil {
+			return
+		}
+		timer.Reset(time.Until(next))
+	}
+}
+
+// wakeFlush makes flushSchedule recalculate next flush time, e.g. for new subscribers
+func (m *dmManager) wakeFlush() {
+	select {
+	case m.flush_wake <- struct{}{}:
+	default:
 	}
 }
 
@@ -88,28 +149,19 @@ func (m *dmManager) safeRun(job func()) {
 	job()
 }
 
-func (m *dmManager) flushData() {
-	new_cache := make(map[uint32][]client.MsgSubscribeBatch)
-	for sub_id, cache := range m.cache {
-		batch := client.MsgSubscribeBatch{}
-		for _, item := range cache {
-			batch.CombineWith(&item)
+// flushData flushes subscribers whose flush interval elapsed, returns time of next flush
+func (m *dmManager) flushData(now time.Time) time.Time {
+	next := now.Add(flushInterval())
+	for _, sub := range m.subscribers {
+		if !now.Before(sub.next_flush) {
+			sub.flush()
+			sub.next_flush = now.Add(sub.flush_interval)
 		}
-		if len(batch.Msgs) == 0 {
-			continue
+		if sub.next_flush.Before(next) {
+			next = sub.next_flush
 		}
-
-		if mailbox, ok := m.mailbox[sub_id]; ok {
-			select {
-			case mailbox <- batch:
-				continue // batch successfully send to mailbox
-			default:
-				new_cache[sub_id] = append(new_cache[sub_id], batch)
-			}
-		}
-		// drop cached msgs if subscriber not exist
 	}
-	m.cache = new_cache
+	return next
 }
 
 func (m *dmManager) onServerShutdown() {
@@ -119,29 +171,157 @@ func (m *dmManager) onServerShutdown() {
 		}
 	}()
 
-	// clear cache
-	m.cache = make(map[uint32][]client.MsgSubscribeBatch)
-
-	// clear all subscribers
+	// clear all subscriptions
 	m.subs = make(map[int]map[uint32]*subscriberInfo)
 
-	// close all mailbox
-	for _, mailbox := range m.mailbox {
-		close(mailbox)
+	// close all mailbox and clear cache
+	for _, sub := range m.subscribers {
+		if sub.mailbox != nil {
+			close(sub.mailbox)
+		}
+		if sub.expire != nil {
+			sub.expire.Stop()
+		}
 	}
-	m.mailbox = make(map[uint32]subMailbox)
+	m.subscribers = make(map[uint32]*subscriber)
 }
 
-func (m *dmManager) AllocSubscriberID(mailbox subMailbox) (ret uint32, err error) {
+func (m *dmManager) AllocSubscriberID(mailbox subMailbox, conf *subscriberConf) (ret uint32, err error) {
+	if conf == nil {
+		conf = &subscriberConf{}
+	}
 	err = m.ExecJob(func() {
 		ret = atomic.AddUint32(&(m.next_id), 1)
-		m.mailbox[ret] = mailbox
+		m.subscribers[ret] = newSubscriber(ret, mailbox, conf)
+		m.wakeFlush()
 	})
 	return
 }
 
-func (m *dmManager) ClearSubscribe(sub_id uint32) error {
-	return m.ExecJob(func() { m.clearSubBySubID(sub_id) })
+// Ack drops retained batches of sub_id whose seq is not greater than seq.
+func (m *dmManager) Ack(sub_id uint32, seq uint64) {
+	m.PostJob(func() {
+		if sub, ok := m.subscribers[sub_id]; ok {
+			sub.ackTo(seq)
+		}
+	})
+}
+
+// Redelivered drops batches redelivered on resume up to seq if sub_id is not in ack mode,
+// otherwise they are kept until acked.
+func (m *dmManager) Redelivered(sub_id uint32, seq uint64) {
+	m.PostJob(func() {
+		if sub, ok := m.subscribers[sub_id]; ok && !sub.ack {
+			sub.ackTo(seq)
+		}
+	})
+}
+
+// Detach is called when the connection receiving mailbox of sub_id is broken. The subscriber
+// is kept with its msgs cached for gConf.ResumeTimeout so that it could be resumed later,
+// pending is the batch failed to be sent and will be redelivered on resume.
+func (m *dmManager) Detach(sub_id uint32, mailbox subMailbox, pending *client.MsgSubscribeBatch) {
+	m.PostJob(func() {
+		sub, ok := m.subscribers[sub_id]
+		if !ok || sub.mailbox != mailbox {
+			return // subscriber logged out or already resumed by another connection
+		}
+
+		if gConf.ResumeTimeout <= 0 {
+			m.logout(sub_id)
+			return
+		}
+
+		if pending != nil && len(pending.Msgs) > 0 && !sub.ack {
+			sub.retain(pending) // already retained in ack mode
+		}
+		sub.detachMailbox()
+		sub.expire = time.AfterFunc(gConf.ResumeTimeout, func() {
+			m.PostJob(func() {
+				if m.subscribers[sub_id] == sub && sub.mailbox == nil {
+					logger().Printf("subscriber %d not resumed in %v, logout", sub_id, gConf.ResumeTimeout)
+					m.logout(sub_id)
+				}
+			})
+		})
+		logger().Printf("subscriber %d detached, wait for resume", sub_id)
+	})
+}
+
+// Resume attaches a new mailbox to an existing subscriber, cached msgs are delivered to it
+// from next flush. If the subscriber is still attached to another connection, the old
+// mailbox is closed. Retained batches after last_seq are returned for redelivery. Name and
+// addr are of the resuming client.
+func (m *dmManager) Resume(sub_id uint32, mailbox subMailbox, last_seq uint64, name, addr string) (redeliver []client.MsgSubscribeBatch, ok bool, err error) {
+	err = m.ExecJob(func() {
+		sub, exist := m.subscribers[sub_id]
+		if !exist {
+			return
+		}
+		if sub.expire != nil {
+			sub.expire.Stop()
+			sub.expire = nil
+		}
+		if old := sub.mailbox; old != nil {
+			sub.detachMailbox()
+			close(old)
+		}
+		sub.mailbox = mailbox
+		sub.name, sub.addr = name, addr
+		sub.next_flush = time.Time{}
+		m.wakeFlush()
+		sub.ackTo(last_seq)
+		for _, item := range sub.unacked {
+			redeliver = append(redeliver, item.Clone())
+		}
+		ok = true
+	})
+	return
+}
+
+// ReplaceSubscribe replaces all subscriptions of sub_id with infos of room_ids, recent msgs
+// are backfilled only for rooms newly added to its subscriptions.
+func (m *dmManager) ReplaceSubscribe(sub_id uint32, room_ids []int, infos []*subscriberInfo) {
+	m.PostJob(func() {
+		if _, ok := m.subscribers[sub_id]; !ok {
+			return // subscriber already logged out
+		}
+		keep := make(map[int]bool, len(room_ids))
+		for _, room_id := range room_ids {
+			keep[room_id] = true
+		}
+
+		subscribed := make(map[int]bool)
+		for room_id, room_sub := range m.subs {
+			if _, ok := room_sub[sub_id]; !ok {
+				continue
+			}
+			subscribed[room_id] = true
+			if keep[room_id] {
+				continue
+			}
+			delete(room_sub, sub_id)
+			if len(room_sub) == 0 {
+				delete(m.subs, room_id)
+				room_id := room_id
+				gClientMgr.PostJob(func() { gClientMgr.ReleaseClient(room_id) })
+			}
+		}
+
+		for idx, room_id := range room_ids {
+			if _, ok := m.subs[room_id]; !ok {
+				m.subs[room_id] = make(map[uint32]*subscriberInfo)
+			}
+			m.subs[room_id][sub_id] = infos[idx]
+			if subscribed[room_id] {
+				continue
+			}
+			subscribed[room_id] = true
+			m.backfill(room_id, infos[idx], infos[idx])
+			room_id := room_id
+			gClientMgr.PostJob(func() { gClientMgr.AddClient(sub_id, room_id) })
+		}
+	})
 }
 
 func (m *dmManager) clearSubBySubID(sub_id uint32) {
@@ -151,6 +331,9 @@ func (m *dmManager) clearSubBySubID(sub_id uint32) {
 			delete(room_sub, sub_id)
 			if len(room_sub) > 0 {
 				new_subs[room_id] = room_sub
+			} else {
+				room_id := room_id
+				gClientMgr.PostJob(func() { gClientMgr.ReleaseClient(room_id) })
 			}
 		} else {
 			new_subs[room_id] = room_sub
@@ -159,46 +342,297 @@ func (m *dmManager) clearSubBySubID(sub_id uint32) {
 	m.subs = new_subs
 }
 
-func (m *dmManager) SubscribeRoom(room_id int, sub_id uint32, cmds []string) {
+func (m *dmManager) Subscriptions(sub_id uint32) (ret []client.MsgSubscription, err error) {
+	err = m.ExecJob(func() {
+		for room_id, room_sub := range m.subs {
+			if info, ok := room_sub[sub_id]; ok {
+				ret = append(ret, client.MsgSubscription{
+					RoomID:  room_id,
+					Cmds:    append([]string{}, info.cmds...),
+					Filters: append([]client.MsgFilter{}, info.filters...),
+					Fields:  append([]string{}, info.fields...),
+				})
+			}
+		}
+	})
+	return
+}
+
+func (m *dmManager) RoomSubscriberCount(room_id int) (ret int, err error) {
+	err = m.ExecJob(func() { ret = len(m.subs[room_id]) })
+	return
+}
+
+// AdminSubscribers returns all subscribers with their subscribed rooms, ordered by id
+func (m *dmManager) AdminSubscribers() (ret []client.MsgAdminSubscriber, err error) {
+	err = m.ExecJob(func() {
+		rooms := make(map[uint32][]int)
+		for room_id, room_sub := range m.subs {
+			for sub_id := range room_sub {
+				rooms[sub_id] = append(rooms[sub_id], room_id)
+			}
+		}
+		for _, sub := range m.subscribers {
+			sort.Ints(rooms[sub.id])
+			ret = append(ret, client.MsgAdminSubscriber{
+				SubscriberID: sub.id,
+				Name:         sub.name,
+				Addr:         sub.addr,
+				Rooms:        rooms[sub.id],
+				Detached:     sub.mailbox == nil,
+				Ack:          sub.ack,
+				Overflow:     sub.policy,
+				CacheMsgs:    len(sub.cache),
+				CacheBytes:   sub.cache_bytes,
+				Unacked:      len(sub.unacked),
+			})
+		}
+		sort.Slice(ret, func(i, j int) bool { return ret[i].SubscriberID < ret[j].SubscriberID })
+	})
+	return
+}
+
+// RoomSubscriberCounts returns number of subscribers of each subscribed room
+func (m *dmManager) RoomSubscriberCounts() (ret map[int]int, err error) {
+	err = m.ExecJob(func() {
+		ret = make(map[int]int, len(m.subs))
+		for room_id, room_sub := range m.subs {
+			ret[room_id] = len(room_sub)
+		}
+	})
+	return
+}
+
+// DropRoom removes all subscriptions of a room and notifies its subscribers with
+// MSG_TYPE_WS_DISCONNECT, the upstream connection should be closed by caller. It reports
+// whether the room had any subscription.
+func (m *dmManager) DropRoom(room_id int) (dropped bool, err error) {
+	err = m.ExecJob(func() {
+		_, dropped = m.subs[room_id]
+		batch := client.MsgSubscribeBatch{Msgs: []client.MsgSubscribeData{{RoomID: room_id, MsgType: client.MSG_TYPE_WS_DISCONNECT}}}
+		for sub_id := range m.subs[room_id] {
+			m.cacheBatch(sub_id, &batch)
+		}
+		delete(m.subs, room_id)
+		delete(m.history, room_id)
+		delete(m.room_msgs, room_id)
+	})
+	return
+}
+
+func (m *dmManager) SubscribeRoom(room_id int, info *subscriberInfo) {
 	m.PostJob(func() {
-		info := &subscriberInfo{
-			id:   sub_id,
-			cmds: append([]string{}, cmds...),
+		if _, ok := m.subscribers[info.id]; !ok {
+			return // subscriber already logged out
 		}
 		if _, ok := m.subs[room_id]; !ok {
 			m.subs[room_id] = make(map[uint32]*subscriberInfo)
 		}
-		m.subs[room_id][sub_id] = info
+		m.subs[room_id][info.id] = info
+		m.backfill(room_id, info, info)
 
-		go gClientMgr.AddClient(sub_id, room_id)
+		gClientMgr.PostJob(func() { gClientMgr.AddClient(info.id, room_id) })
 	})
 }
 
-func (m *dmManager) Logout(sub_id uint32) {
+// AddSubscribe subscribes cmds of a room in addition to current subscriptions of sub_id,
+// filters and fields of the room are replaced if new ones are given.
+func (m *dmManager) AddSubscribe(room_id int, add *subscriberInfo) {
+	m.PostJob(func() {
+		sub_id := add.id
+		if _, ok := m.subscribers[sub_id]; !ok {
+			return // subscriber already logged out
+		}
+		if _, ok := m.subs[room_id]; !ok {
+			m.subs[room_id] = make(map[uint32]*subscriberInfo)
+		}
+		info, ok := m.subs[room_id][sub_id]
+		if !ok {
+			m.subs[room_id][sub_id] = add
+			m.backfill(room_id, add, add)
+			gClientMgr.PostJob(func() { gClientMgr.AddClient(sub_id, room_id) })
+			return
+		}
+		for _, cmd := range add.cmds {
+			if !containsCmd(info.cmds, cmd) {
+				info.cmds = append(info.cmds, cmd)
+			}
+		}
+		info.matcher = compileCmdMatcher(info.cmds)
+		if len(add.filters) > 0 {
+			info.filters, info.filter = add.filters, add.filter
+		}
+		if len(add.fields) > 0 {
+			info.fields, info.proj = add.fields, add.proj
+		}
+		m.backfill(room_id, add, info)
+	})
+}
+
+// backfill caches recent msgs of a room requested by req for subscriber of info, so that
+// they are delivered before live msgs.
+func (m *dmManager) backfill(room_id int, req *subscriberInfo, info *subscriberInfo) {
+	if req.backfill <= 0 && req.since <= 0 {
+		return
+	}
+	history, ok := m.history[room_id]
+	if !ok {
+		return
+	}
+	// match by requested cmds, but filter and project as the merged subscription does
+	tmp := *info
+	tmp.matcher = req.matcher
+	msgs := history.backfill(room_id, &tmp, req.backfill, req.since)
+	if len(msgs) == 0 {
+		return
+	}
+	m.cacheBatch(info.id, &client.MsgSubscribeBatch{Msgs: msgs})
+}
+
+// ClearRoom drops recent msgs and msg counters of a room, called when upstream connection is
+// released
+func (m *dmManager) ClearRoom(room_id int) {
 	m.PostJob(func() {
-		m.clearSubBySubID(sub_id)
+		delete(m.history, room_id)
+		delete(m.room_msgs, room_id)
+	})
+}
 
-		batch := client.MsgSubscribeBatch{}
-		if cache, ok := m.cache[sub_id]; ok {
-			delete(m.cache, sub_id)
-			for _, item := range cache {
-				batch.CombineWith(&item)
+// countRoomMsg counts a msg received from a room for metrics, cmds of a room beyond
+// maxRoomMsgCmds are counted as roomMsgOtherCmd.
+func (m *dmManager) countRoomMsg(room_id int, cmd string) {
+	counts, ok := m.room_msgs[room_id]
+	if !ok {
+		counts = make(map[string]uint64)
+		m.room_msgs[room_id] = counts
+	}
+	if _, ok := counts[cmd]; !ok && len(counts) >= maxRoomMsgCmds {
+		cmd = roomMsgOtherCmd
+	}
+	counts[cmd]++
+}
+
+// Metrics takes a snapshot of subscribers and room msg counts
+func (m *dmManager) Metrics() (ret dmMetrics, err error) {
+	err = m.ExecJob(func() {
+		ret.job_queue = len(m.job_ch)
+		ret.rooms = len(m.subs)
+		ret.room_msgs = make(map[int]map[string]uint64, len(m.room_msgs))
+		for room_id, counts := range m.room_msgs {
+			ret.room_msgs[room_id] = make(map[string]uint64, len(counts))
+			for cmd, count := range counts {
+				ret.room_msgs[room_id][cmd] = count
 			}
 		}
+		for _, sub := range m.subscribers {
+			item := subscriberMetrics{
+				id:          sub.id,
+				detached:    sub.mailbox == nil,
+				cache_msgs:  len(sub.cache),
+				cache_bytes: sub.cache_bytes,
+				mailbox:     len(sub.mailbox),
+				unacked:     len(sub.unacked),
+			}
+			if sub.ack && len(sub.unacked) > 0 {
+				item.lag = sub.seq - sub.unacked[0].Seq + 1
+			}
+			ret.subscribers = append(ret.subscribers, item)
+		}
+		sort.Slice(ret.subscribers, func(i, j int) bool { return ret.subscribers[i].id < ret.subscribers[j].id })
+	})
+	return
+}
+
+func (m *dmManager) addHistory(room_id int, cmd string, data []byte) {
+	if gConf.HistorySize <= 0 {
+		return
+	}
+	history, ok := m.history[room_id]
+	if !ok {
+		history = newRoomHistory()
+		m.history[room_id] = history
+	}
+	history.add(gConf.HistorySize, time.Now(), cmd, data)
+}
+
+// RemoveSubscribe unsubscribes cmds of a room, the whole room is unsubscribed if cmds is
+// empty or no cmds remain subscribed after removal.
+func (m *dmManager) RemoveSubscribe(room_id int, sub_id uint32, cmds []string) {
+	m.PostJob(func() {
+		room_sub, ok := m.subs[room_id]
+		if !ok {
+			return
+		}
+		info, ok := room_sub[sub_id]
+		if !ok {
+			return
+		}
 
-		if mailbox, ok := m.mailbox[sub_id]; ok {
-			if len(batch.Msgs) > 0 {
-				select {
-				case mailbox <- batch:
-					func() {}() // noop
-				default:
-					func() {}() // noop
-				}
+		remain := []string{}
+		for _, cmd := range info.cmds {
+			if !containsCmd(cmds, cmd) {
+				remain = append(remain, cmd)
 			}
-			delete(m.mailbox, sub_id)
-			close(mailbox)
+		}
+		info.cmds = remain
+		info.matcher = compileCmdMatcher(remain)
+		if len(cmds) > 0 && len(remain) > 0 {
+			return
+		}
+
+		delete(room_sub, sub_id)
+		if len(room_sub) == 0 {
+			delete(m.subs, room_id)
+			gClientMgr.PostJob(func() { gClientMgr.ReleaseClient(room_id) })
+		}
+	})
+}
+
+func containsCmd(cmds []string, cmd string) bool {
+	for _, item := range cmds {
+		if item == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+// Kick logs out a subscriber for admin. Server side subscribers of sinks are not kicked since
+// nothing would log them in again.
+func (m *dmManager) Kick(sub_id uint32) (found, internal bool, err error) {
+	err = m.ExecJob(func() {
+		sub, ok := m.subscribers[sub_id]
+		if !ok {
+			return
+		}
+		found, internal = true, len(sub.addr) == 0
+		if !internal {
+			m.logout(sub_id)
 		}
 	})
+	return
+}
+
+func (m *dmManager) Logout(sub_id uint32) {
+	m.PostJob(func() { m.logout(sub_id) })
+}
+
+func (m *dmManager) logout(sub_id uint32) {
+	m.clearSubBySubID(sub_id)
+
+	sub, ok := m.subscribers[sub_id]
+	if !ok {
+		return
+	}
+	delete(m.subscribers, sub_id)
+	if sub.expire != nil {
+		sub.expire.Stop()
+	}
+
+	if sub.mailbox != nil {
+		sub.flush() // send remaining msgs as much as possible
+		close(sub.mailbox)
+	}
 }
 
 func (m *dmManager) UpdateRommState(room_id int, msg_type int, room_info *dm.RoomInfo, sub_id_list []uint32) {
@@ -224,7 +658,7 @@ func (m *dmManager) UpdateRommState(room_id int, msg_type int, room_info *dm.Roo
 			}
 		}
 		for _, id := range sub_id_list {
-			m.cache[id] = append(m.cache[id], batch.Clone())
+			m.cacheBatch(id, &batch)
 		}
 	})
 }
@@ -242,13 +676,25 @@ func (m *dmManager) OnRoomMsg(room_id int, cmd string, data []byte) {
 	}
 
 	m.PostJob(func() {
+		m.countRoomMsg(room_id, cmd)
+		m.addHistory(room_id, cmd, data)
 		if sub_list, ok := m.subs[room_id]; ok {
+			projected := make(map[string]*client.MsgSubscribeBatch) // projection key => batch
 			for _, item := range sub_list {
-				for _, tmp := range item.cmds {
-					if tmp == cmd {
-						m.cache[item.id] = append(m.cache[item.id], batch.Clone())
-					}
+				if !item.accept(cmd, data) {
+					continue
+				}
+				if item.proj == nil {
+					m.cacheBatch(item.id, &batch)
+					continue
+				}
+				tmp, ok := projected[item.proj.key]
+				if !ok {
+					tmp = &client.MsgSubscribeBatch{Msgs: []client.MsgSubscribeData{batch.Msgs[0]}}
+					tmp.Msgs[0].Data = item.proj.apply(data)
+					projected[item.proj.key] = tmp
 				}
+				m.cacheBatch(item.id, tmp)
 			}
 		}
 	})
@@ -267,10 +713,29 @@ func (m *dmManager) OnRoomLiveStateChange(room_id int, cmd string, data []byte)
 	}
 
 	m.PostJob(func() {
+		m.countRoomMsg(room_id, cmd)
+		m.addHistory(room_id, cmd, data)
 		if sub_list, ok := m.subs[room_id]; ok {
 			for _, item := range sub_list {
-				m.cache[item.id] = append(m.cache[item.id], batch.Clone())
+				m.cacheBatch(item.id, &batch)
 			}
 		}
 	})
 }
+
+func (m *dmManager) cacheBatch(sub_id uint32, batch *client.MsgSubscribeBatch) {
+	sub, ok := m.subscribers[sub_id]
+	if !ok {
+		return
+	}
+	dropped := sub.dropped
+	ok = sub.push(batch.Msgs)
+	if sub.dropped > dropped {
+		countMetric(&gCounters.dropped_msgs, uint64(sub.dropped-dropped))
+	}
+	if !ok {
+		countMetric(&gCounters.overflow_disconnects, 1)
+		logger().Printf("cache of subscriber %d overflowed, disconnect", sub_id)
+		m.logout(sub_id)
+	}
+}
@@ -0,0 +1,270 @@
+// Package dmmock is a scriptable fake of bilibili live danmaku server, it emits DANMU_MSG,
+// SEND_GIFT, LIVE and PREPARING events and simulates disconnects so that brelay could be
+// tested without network access.
+package dmmock
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	dm "github.com/zerozwt/BLiveDanmaku"
+)
+
+var ErrRoomNotFound error = errors.New("dmmock: room not found")
+var ErrDialFailed error = errors.New("dmmock: dial failed")
+var ErrDisconnected error = errors.New("dmmock: disconnected by server")
+
+// Handler receives events of a connection, it has the same semantics as brelay's upstream
+// handler: OnMsg receives every raw msg, OnLiveStateChange is called additionally for LIVE
+// and PREPARING, OnDisconnect is not called if the connection is closed by Close.
+type Handler struct {
+	OnMsg             func(conn *Conn, data []byte)
+	OnLiveStateChange func(conn *Conn, cmd string)
+	OnDisconnect      func(conn *Conn, err error)
+}
+
+type Server struct {
+	sync.Mutex
+	rooms map[int]*Room
+}
+
+func NewServer() *Server {
+	return &Server{rooms: make(map[int]*Room)}
+}
+
+// Room returns the fake live room of room_id, creates it if not exist
+func (s *Server) Room(room_id int) *Room {
+	s.Lock()
+	defer s.Unlock()
+	if ret, ok := s.rooms[room_id]; ok {
+		return ret
+	}
+	ret := &Room{conns: make(map[*Conn]struct{})}
+	ret.info.Base.RoomID = room_id
+	ret.info.Base.Title = "dmmock room"
+	ret.info.Liver.Base.Name = "dmmock"
+	s.rooms[room_id] = ret
+	return ret
+}
+
+// Dial connects to a room created by Room
+func (s *Server) Dial(room_id int, handler Handler) (*Conn, error) {
+	s.Lock()
+	room, ok := s.rooms[room_id]
+	s.Unlock()
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+	return room.dial(handler)
+}
+
+type Room struct {
+	sync.Mutex
+	info       dm.RoomInfo
+	conns      map[*Conn]struct{}
+	fail_dials int
+	dials      int
+}
+
+type Conn struct {
+	room    *Room
+	handler Handler
+	closed  int32
+}
+
+func (c *Conn) Room() *dm.RoomInfo {
+	c.room.Lock()
+	defer c.room.Unlock()
+	ret := c.room.info
+	return &ret
+}
+
+func (c *Conn) Close() {
+	if atomic.CompareAndSwapInt32(&(c.closed), 0, 1) {
+		c.room.Lock()
+		defer c.room.Unlock()
+		delete(c.room.conns, c)
+	}
+}
+
+func (r *Room) dial(handler Handler) (*Conn, error) {
+	r.Lock()
+	defer r.Unlock()
+	r.dials++
+	if r.fail_dials > 0 {
+		r.fail_dials--
+		return nil, ErrDialFailed
+	}
+	ret := &Conn{room: r, handler: handler}
+	r.conns[ret] = struct{}{}
+	return ret, nil
+}
+
+// FailDials makes next n dials to the room fail
+func (r *Room) FailDials(n int) {
+	r.Lock()
+	defer r.Unlock()
+	r.fail_dials = n
+}
+
+// Dials returns number of dials to the room, including failed ones
+func (r *Room) Dials() int {
+	r.Lock()
+	defer r.Unlock()
+	return r.dials
+}
+
+// Conns returns number of alive connections to the room
+func (r *Room) Conns() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.conns)
+}
+
+func (r *Room) aliveConns() []*Conn {
+	r.Lock()
+	defer r.Unlock()
+	ret := make([]*Conn, 0, len(r.conns))
+	for conn := range r.conns {
+		ret = append(ret, conn)
+	}
+	return ret
+}
+
+// SendRaw sends raw json msg to all connections
+func (r *Room) SendRaw(data []byte) {
+	for _, conn := range r.aliveConns() {
+		if atomic.LoadInt32(&(conn.closed)) == 0 && conn.handler.OnMsg != nil {
+			conn.handler.OnMsg(conn, data)
+		}
+	}
+}
+
+// Send sends a msg of cmd with body in field "data"
+func (r *Room) Send(cmd string, data interface{}) {
+	r.SendRaw(marshal(map[string]interface{}{"cmd": cmd, "data": data}))
+}
+
+// Danmaku sends a DANMU_MSG
+func (r *Room) Danmaku(uid int, uname, text string) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	r.SendRaw(marshal(map[string]interface{}{
+		"cmd": dm.CMD_DANMU_MSG,
+		"info": []interface{}{
+			[]interface{}{0, 1, 25, 16777215, now, 0, 0, "", 0, 0, 0, "", 0, "{}", "{}"},
+			text,
+			[]interface{}{uid, uname, 0, 0, 0, 10000, 1, ""},
+			[]interface{}{},
+			[]interface{}{0, 0, 9868950, ">50000", 0},
+			[]interface{}{"", ""},
+		},
+	}))
+}
+
+// Gift sends a SEND_GIFT, price is in gold coins of a single gift
+func (r *Room) Gift(uid int, uname, gift_name string, num, price int) {
+	r.Send(dm.CMD_SEND_GIFT, map[string]interface{}{
+		"uid":        uid,
+		"uname":      uname,
+		"giftName":   gift_name,
+		"action":     "投喂",
+		"num":        num,
+		"price":      price,
+		"total_coin": num * price,
+		"coin_type":  "gold",
+		"timestamp":  time.Now().Unix(),
+	})
+}
+
+// Live starts live streaming of the room
+func (r *Room) Live() {
+	r.setLiveStatus(dm.CMD_LIVE, 1)
+}
+
+// Preparing stops live streaming of the room
+func (r *Room) Preparing() {
+	r.setLiveStatus(dm.CMD_PREPARING, 0)
+}
+
+func (r *Room) setLiveStatus(cmd string, status int) {
+	r.Lock()
+	r.info.Base.LiveStatus = status
+	if status == 1 {
+		r.info.Base.LiveStartTime = time.Now().Unix()
+	}
+	room_id := r.info.Base.RoomID
+	r.Unlock()
+
+	r.SendRaw(marshal(map[string]interface{}{"cmd": cmd, "roomid": room_id}))
+	for _, conn := range r.aliveConns() {
+		if atomic.LoadInt32(&(conn.closed)) == 0 && conn.handler.OnLiveStateChange != nil {
+			conn.handler.OnLiveStateChange(conn, cmd)
+		}
+	}
+}
+
+// Disconnect breaks all connections to the room with ErrDisconnected
+func (r *Room) Disconnect() {
+	for _, conn := range r.aliveConns() {
+		if atomic.CompareAndSwapInt32(&(conn.closed), 0, 1) {
+			r.Lock()
+			delete(r.conns, conn)
+			r.Unlock()
+			if conn.handler.OnDisconnect != nil {
+				conn.handler.OnDisconnect(conn, ErrDisconnected)
+			}
+		}
+	}
+}
+
+// Event is a step of a script played by Room.Play
+type Event struct {
+	Delay time.Duration // wait before the event
+	Apply func(r *Room)
+}
+
+func DanmakuEvent(delay time.Duration, uid int, uname, text string) Event {
+	return Event{Delay: delay, Apply: func(r *Room) { r.Danmaku(uid, uname, text) }}
+}
+
+func GiftEvent(delay time.Duration, uid int, uname, gift_name string, num, price int) Event {
+	return Event{Delay: delay, Apply: func(r *Room) { r.Gift(uid, uname, gift_name, num, price) }}
+}
+
+func LiveEvent(delay time.Duration) Event {
+	return Event{Delay: delay, Apply: (*Room).Live}
+}
+
+func PreparingEvent(delay time.Duration) Event {
+	return Event{Delay: delay, Apply: (*Room).Preparing}
+}
+
+func DisconnectEvent(delay time.Duration) Event {
+	return Event{Delay: delay, Apply: (*Room).Disconnect}
+}
+
+// Play applies events of script in order until all events are applied or stop is closed, the
+// script is repeated if loop is true.
+func (r *Room) Play(script []Event, loop bool, stop chan struct{}) {
+	for {
+		for _, event := range script {
+			select {
+			case <-time.After(event.Delay):
+				event.Apply(r)
+			case <-stop:
+				return
+			}
+		}
+		if !loop || len(script) == 0 {
+			return
+		}
+	}
+}
+
+func marshal(obj interface{}) []byte {
+	ret, _ := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(obj)
+	return ret
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/zerozwt/brelay/client"
+)
+
+// jsonFilter is a compiled client.MsgFilter evaluated against raw json of room msgs
+type jsonFilter struct {
+	cmd  *cmdMatcher // nil for all cmds
+	path []interface{}
+	op   string
+	args []string
+	nums []float64 // args parsed as numbers, nil if any of args is not a number
+}
+
+func compileFilters(filters []client.MsgFilter) ([]*jsonFilter, error) {
+	ret := make([]*jsonFilter, 0, len(filters))
+	for idx := range filters {
+		tmp, err := compileFilter(&filters[idx])
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, tmp)
+	}
+	return ret, nil
+}
+
+func compileFilter(filter *client.MsgFilter) (*jsonFilter, error) {
+	ret := &jsonFilter{
+		path: parseJSONPath(filter.Path),
+		op:   filter.Op,
+		args: filter.Args,
+	}
+	if len(filter.Cmd) > 0 {
+		ret.cmd = compileCmdMatcher([]string{filter.Cmd})
+	}
+	if len(ret.path) == 0 {
+		return nil, errors.New("empty filter path")
+	}
+
+	switch filter.Op {
+	case client.FILTER_OP_EXISTS:
+	case client.FILTER_OP_EQ, client.FILTER_OP_NE, client.FILTER_OP_GT, client.FILTER_OP_GE, client.FILTER_OP_LT, client.FILTER_OP_LE:
+		if len(filter.Args) != 1 {
+			return nil, fmt.Errorf("filter op %s requires exactly 1 arg", filter.Op)
+		}
+	case client.FILTER_OP_IN:
+		if len(filter.Args) == 0 {
+			return nil, fmt.Errorf("filter op %s requires at least 1 arg", filter.Op)
+		}
+	default:
+		return nil, fmt.Errorf("unknown filter op %s", filter.Op)
+	}
+
+	for _, arg := range filter.Args {
+		num, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			ret.nums = nil
+			break
+		}
+		ret.nums = append(ret.nums, num)
+	}
+	return ret, nil
+}
+
+// parseJSONPath splits a dot separated json path, numeric segments are array indexes
+func parseJSONPath(path string) []interface{} {
+	ret := []interface{}{}
+	if len(path) == 0 {
+		return ret
+	}
+	for _, key := range strings.Split(path, ".") {
+		if idx, err := strconv.Atoi(key); err == nil {
+			ret = append(ret, idx)
+		} else {
+			ret = append(ret, key)
+		}
+	}
+	return ret
+}
+
+// matchFilters returns true if data of cmd passes all filters applied to cmd
+func matchFilters(filters []*jsonFilter, cmd string, data []byte) bool {
+	for _, filter := range filters {
+		if filter.cmd != nil && !filter.cmd.Match(cmd) {
+			continue
+		}
+		if !filter.match(data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *jsonFilter) match(data []byte) bool {
+	value := jsoniter.ConfigCompatibleWithStandardLibrary.Get(data, f.path...)
+	if value.ValueType() == jsoniter.InvalidValue {
+		return f.op == client.FILTER_OP_NE
+	}
+
+	switch f.op {
+	case client.FILTER_OP_EXISTS:
+		return true
+	case client.FILTER_OP_EQ:
+		return f.compare(value, 0) == 0
+	case client.FILTER_OP_NE:
+		return f.compare(value, 0) != 0
+	case client.FILTER_OP_GT:
+		return f.comparable(value) && f.compare(value, 0) > 0
+	case client.FILTER_OP_GE:
+		return f.comparable(value) && f.compare(value, 0) >= 0
+	case client.FILTER_OP_LT:
+		return f.comparable(value) && f.compare(value, 0) < 0
+	case client.FILTER_OP_LE:
+		return f.comparable(value) && f.compare(value, 0) <= 0
+	case client.FILTER_OP_IN:
+		for idx := range f.args {
+			if f.compare(value, idx) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// comparable returns false if value is a number but args are not
+func (f *jsonFilter) comparable(value jsoniter.Any) bool {
+	return value.ValueType() != jsoniter.NumberValue || f.nums != nil
+}
+
+// compare compares value with args[idx], numerically if both are numbers
+func (f *jsonFilter) compare(value jsoniter.Any, idx int) int {
+	if value.ValueType() == jsoniter.NumberValue && f.nums != nil {
+		num := value.ToFloat64()
+		switch {
+		case num < f.nums[idx]:
+			return -1
+		case num > f.nums[idx]:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(value.ToString(), f.args[idx])
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/zerozwt/brelay/client"
+)
+
+func TestJSONFilter(t *testing.T) {
+	danmaku := []byte(`{"cmd":"DANMU_MSG","info":[[0,1,25],"hello",[12345,"uname",0]]}`)
+	gift := []byte(`{"cmd":"SEND_GIFT","data":{"giftName":"flower","price":1000,"uid":12345}}`)
+
+	cases := []struct {
+		filters []client.MsgFilter
+		cmd     string
+		data    []byte
+		match   bool
+	}{
+		{nil, "DANMU_MSG", danmaku, true},
+		{[]client.MsgFilter{{Path: "info.2.0", Op: client.FILTER_OP_EQ, Args: []string{"12345"}}}, "DANMU_MSG", danmaku, true},
+		{[]client.MsgFilter{{Path: "info.2.0", Op: client.FILTER_OP_IN, Args: []string{"1", "2"}}}, "DANMU_MSG", danmaku, false},
+		{[]client.MsgFilter{{Path: "info.1", Op: client.FILTER_OP_EQ, Args: []string{"hello"}}}, "DANMU_MSG", danmaku, true},
+		{[]client.MsgFilter{{Path: "data.price", Op: client.FILTER_OP_GE, Args: []string{"1000"}}}, "SEND_GIFT", gift, true},
+		{[]client.MsgFilter{{Path: "data.price", Op: client.FILTER_OP_GT, Args: []string{"1000"}}}, "SEND_GIFT", gift, false},
+		{[]client.MsgFilter{{Path: "data.price", Op: client.FILTER_OP_LT, Args: []string{"cheap"}}}, "SEND_GIFT", gift, false},
+		{[]client.MsgFilter{{Path: "data.coin_type", Op: client.FILTER_OP_EXISTS}}, "SEND_GIFT", gift, false},
+		{[]client.MsgFilter{{Path: "data.coin_type", Op: client.FILTER_OP_NE, Args: []string{"gold"}}}, "SEND_GIFT", gift, true},
+		{[]client.MsgFilter{{Cmd: "SEND_GIFT", Path: "data.price", Op: client.FILTER_OP_GT, Args: []string{"5000"}}}, "DANMU_MSG", danmaku, true},
+		{[]client.MsgFilter{{Cmd: "SEND_GIFT", Path: "data.price", Op: client.FILTER_OP_GT, Args: []string{"5000"}}}, "SEND_GIFT", gift, false},
+	}
+
+	for idx, item := range cases {
+		filters, err := compileFilters(item.filters)
+		if err != nil {
+			t.Errorf("case %d: compile filters failed: %v", idx, err)
+			continue
+		}
+		if ret := matchFilters(filters, item.cmd, item.data); ret != item.match {
+			t.Errorf("case %d: expect %v, got %v", idx, item.match, ret)
+		}
+	}
+
+	if _, err := compileFilters([]client.MsgFilter{{Path: "data.price", Op: "like", Args: []string{"1"}}}); err == nil {
+		t.Errorf("unknown filter op should fail to compile")
+	}
+}
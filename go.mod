@@ -3,6 +3,7 @@ module github.com/zerozwt/brelay
 go 1.13
 
 require (
+	github.com/gorilla/websocket v1.4.2
 	github.com/json-iterator/go v1.1.12
 	github.com/tinylib/msgp v1.1.6
 	github.com/zerozwt/BLiveDanmaku v1.0.2
@@ -6,16 +6,23 @@ import (
 	"crypto/cipher"
 	"crypto/sha256"
 	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/zerozwt/brelay/client"
 	"github.com/zerozwt/toyframe"
 )
 
-func getSecret(sub_id uint32) []byte {
-	key := []byte(gConf.LoginKey)
-	if len(key) == 0 {
-		key = []byte(`brelay_key`)
+func loginKey() string {
+	if len(gConf.LoginKey) == 0 {
+		return `brelay_key`
 	}
+	return gConf.LoginKey
+}
+
+func getSecret(sub_id uint32) []byte {
+	key := []byte(loginKey())
 
 	data := make([]byte, len(key)+4)
 	binary.Write(bytes.NewBuffer(data[:0]), binary.BigEndian, sub_id)
@@ -42,15 +49,14 @@ func loginHandler(ctx *toyframe.Context) error {
 		return err
 	}
 
-	mb := make(subMailbox)
-	sub_id, err := gDanmaku.AllocSubscriberID(mb)
+	mb := newMailbox()
+	sub_id, err := gDanmaku.AllocSubscriberID(mb, loginConf(&login_req, ctx.RemoteAddr().String()))
 
 	if err != nil {
 		return err // it has to be a ErrInterrupted
 	} else {
 		logger().Printf("client %s get an subscriber id %d", login_req.ID, sub_id)
 	}
-	defer gDanmaku.Logout(sub_id)
 
 	// send login response
 	login_rsp := client.MsgLoginRsp{
@@ -59,17 +65,135 @@ func loginHandler(ctx *toyframe.Context) error {
 	}
 	if err = ctx.WriteObj(&login_rsp); err != nil {
 		logger().Printf("send login reponse failed: %v", err)
+		gDanmaku.Logout(sub_id)
+		return err
+	}
+
+	return sendBatches(ctx, login_req.ID, sub_id, mb)
+}
+
+func loginConf(req *client.MsgLoginReq, addr string) *subscriberConf {
+	return &subscriberConf{
+		name:           req.ID,
+		addr:           addr,
+		ack:            req.Ack,
+		policy:         req.Overflow,
+		flush_interval: time.Duration(req.FlushInterval) * time.Millisecond,
+	}
+}
+
+func resumeHandler(ctx *toyframe.Context) error {
+	wgAll.Add(1)
+	ctx.AddCloseHandler(wgAll.Done)
+	ctx.SetInterruptor(gServer.CloseChannel())
+
+	// get resume msg
+	req := client.MsgResumeReq{}
+	if err := ctx.ReadObj(&req); err != nil {
+		logger().Printf("read resume request failed: %v", err)
+		return err
+	}
+
+	rsp := client.MsgResumeRsp{Ok: true}
+	mb := newMailbox()
+	redeliver := []client.MsgSubscribeBatch{}
+
+	sec := getSecret(req.SubscriberID)
+	if !bytes.Equal(sec, req.SubscriberSecret) {
+		logger().Printf("resume failed: secret check failed")
+		rsp.Ok = false
+		rsp.Msg = "secret check failed"
+	}
+
+	if rsp.Ok {
+		batches, ok, err := gDanmaku.Resume(req.SubscriberID, mb, req.LastSeq, req.ID, ctx.RemoteAddr().String())
+		if err != nil {
+			return err // it has to be a ErrInterrupted
+		}
+		if !ok {
+			logger().Printf("client %s resume subscriber %d failed: subscriber not found", req.ID, req.SubscriberID)
+			rsp.Ok = false
+			rsp.Msg = "subscriber not found or expired"
+		} else {
+			logger().Printf("client %s resumed subscriber %d, redeliver %d batches", req.ID, req.SubscriberID, len(batches))
+			redeliver = batches
+		}
+	}
+
+	if err := ctx.WriteObj(&rsp); err != nil {
+		logger().Printf("send resume reponse failed: %v", err)
+		if rsp.Ok {
+			gDanmaku.Detach(req.SubscriberID, mb, nil)
+		}
 		return err
 	}
+	if !rsp.Ok {
+		return nil
+	}
 
-	// send batch data
-	for batch := range mb {
-		if err = ctx.WriteObj(&batch); err != nil {
-			logger().Printf("send batch msgs to client %s failed: %v", login_req.ID, err)
+	// redeliver batches before new ones
+	for idx := range redeliver {
+		if err := ctx.WriteObj(&redeliver[idx]); err != nil {
+			logger().Printf("redeliver batch msgs to client %s failed: %v", req.ID, err)
+			gDanmaku.Detach(req.SubscriberID, mb, nil)
 			return err
 		}
 	}
-	return nil
+	if len(redeliver) > 0 {
+		gDanmaku.Redelivered(req.SubscriberID, redeliver[len(redeliver)-1].Seq)
+	}
+
+	return sendBatches(ctx, req.ID, req.SubscriberID, mb)
+}
+
+// sendBatches sends batches from mailbox to client until the mailbox is closed by logout or
+// resume from another connection. The subscriber is detached if the connection is broken.
+func sendBatches(ctx *toyframe.Context, name string, sub_id uint32, mb subMailbox) error {
+	// interrupted reads leave the underlying read running, so the connection is closed on
+	// server shutdown instead to end both reading acks and sending batches
+	ctx.SetInterruptor(nil)
+	stop_ch := make(chan struct{})
+	defer close(stop_ch)
+	go func() {
+		select {
+		case <-gServer.CloseChannel():
+			ctx.Close()
+		case <-stop_ch:
+		}
+	}()
+
+	broken_ch := make(chan struct{})
+	go readAcks(ctx, sub_id, broken_ch)
+
+	for {
+		select {
+		case batch, ok := <-mb:
+			if !ok {
+				return nil
+			}
+			if err := ctx.WriteObj(&batch); err != nil {
+				logger().Printf("send batch msgs to client %s failed: %v", name, err)
+				gDanmaku.Detach(sub_id, mb, &batch)
+				return err
+			}
+		case <-broken_ch:
+			logger().Printf("connection of client %s closed", name)
+			gDanmaku.Detach(sub_id, mb, nil)
+			return nil
+		}
+	}
+}
+
+// readAcks reads acks from client until the connection is broken
+func readAcks(ctx *toyframe.Context, sub_id uint32, broken_ch chan struct{}) {
+	defer close(broken_ch)
+	for {
+		ack := client.MsgAck{}
+		if err := ctx.ReadObj(&ack); err != nil {
+			return
+		}
+		gDanmaku.Ack(sub_id, ack.Seq)
+	}
 }
 
 func logoutHandler(ctx *toyframe.Context) error {
@@ -105,6 +229,85 @@ func logoutHandler(ctx *toyframe.Context) error {
 }
 
 func subscribeHandler(ctx *toyframe.Context) error {
+	return handleSubscribeReq(ctx, "subscribe", doSubscribe)
+}
+
+func subscribeAddHandler(ctx *toyframe.Context) error {
+	return handleSubscribeReq(ctx, "subscribe_add", doSubscribeAdd)
+}
+
+func subscribeRemoveHandler(ctx *toyframe.Context) error {
+	return handleSubscribeReq(ctx, "subscribe_remove", doSubscribeRemove)
+}
+
+func doSubscribe(req *client.MsgSubscribeReq, infos []*subscriberInfo) {
+	room_ids := make([]int, 0, len(req.Rooms))
+	for _, sub := range req.Rooms {
+		room_ids = append(room_ids, sub.RoomID)
+	}
+	gDanmaku.ReplaceSubscribe(req.SubscriberID, room_ids, infos)
+}
+
+func doSubscribeAdd(req *client.MsgSubscribeReq, infos []*subscriberInfo) {
+	for idx, sub := range req.Rooms {
+		gDanmaku.AddSubscribe(sub.RoomID, infos[idx])
+	}
+}
+
+func doSubscribeRemove(req *client.MsgSubscribeReq, _ []*subscriberInfo) {
+	for _, sub := range req.Rooms {
+		gDanmaku.RemoveSubscribe(sub.RoomID, req.SubscriberID, sub.Cmds)
+	}
+}
+
+func listSubscriptionsHandler(ctx *toyframe.Context) error {
+	wgAll.Add(1)
+	ctx.AddCloseHandler(wgAll.Done)
+	ctx.SetInterruptor(gServer.CloseChannel())
+
+	req := client.MsgListSubscriptionsReq{}
+	if err := ctx.ReadObj(&req); err != nil {
+		logger().Printf("read list_subscriptions request failed: %v", err)
+		return err
+	}
+
+	rsp := client.MsgListSubscriptionsRsp{Ok: true}
+
+	sec := getSecret(req.SubscriberID)
+	if !bytes.Equal(sec, req.SubscriberSecret) {
+		logger().Printf("list_subscriptions failed: secret check failed")
+		rsp.Ok = false
+		rsp.Msg = "secret check failed"
+	}
+
+	if rsp.Ok {
+		rooms, err := listSubscriptions(req.SubscriberID)
+		if err != nil {
+			return err // it has to be a ErrInterrupted
+		}
+		rsp.Rooms = rooms
+	}
+
+	if err := ctx.WriteObj(&rsp); err != nil {
+		logger().Printf("send list_subscriptions reponse failed: %v", err)
+	}
+	return nil
+}
+
+// listSubscriptions returns subscriptions of sub_id with upstream states, ordered by room id
+func listSubscriptions(sub_id uint32) ([]client.MsgSubscription, error) {
+	rooms, err := gDanmaku.Subscriptions(sub_id)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range rooms {
+		rooms[idx].State = byte(gClientMgr.RoomState(rooms[idx].RoomID))
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].RoomID < rooms[j].RoomID })
+	return rooms, nil
+}
+
+func handleSubscribeReq(ctx *toyframe.Context, method string, cb func(req *client.MsgSubscribeReq, infos []*subscriberInfo)) error {
 	wgAll.Add(1)
 	ctx.AddCloseHandler(wgAll.Done)
 	ctx.SetInterruptor(gServer.CloseChannel())
@@ -112,28 +315,42 @@ func subscribeHandler(ctx *toyframe.Context) error {
 	// get subscribe msg
 	req := client.MsgSubscribeReq{}
 	if err := ctx.ReadObj(&req); err != nil {
-		logger().Printf("read subscribe request failed: %v", err)
+		logger().Printf("read %s request failed: %v", method, err)
 		return err
 	}
 
 	// check secret
 	sec := getSecret(req.SubscriberID)
 	if !bytes.Equal(sec, req.SubscriberSecret) {
-		logger().Printf("subscribe failed: secret check failed")
+		logger().Printf("%s failed: secret check failed", method)
 		ctx.WriteObj(&client.MsgSubscribeRsp{Ok: false, Msg: "secret check failed"})
 		return nil
 	}
 
-	// clear current subscriptions
-	if gDanmaku.ClearSubscribe(req.SubscriberID) == nil {
-		for _, sub := range req.Rooms {
-			// subscribe
-			gDanmaku.SubscribeRoom(sub.RoomID, req.SubscriberID, sub.Cmds)
-		}
+	// compile subscriptions
+	infos, err := compileSubscriptions(req.SubscriberID, req.Rooms)
+	if err != nil {
+		logger().Printf("%s failed: %v", method, err)
+		ctx.WriteObj(&client.MsgSubscribeRsp{Ok: false, Msg: err.Error()})
+		return nil
 	}
 
+	cb(&req, infos)
+
 	if err := ctx.WriteObj(&client.MsgSubscribeRsp{Ok: true}); err != nil {
-		logger().Printf("send subscribe reponse failed: %v", err)
+		logger().Printf("send %s reponse failed: %v", method, err)
 	}
 	return nil
 }
+
+func compileSubscriptions(sub_id uint32, rooms []client.MsgSubscribeRoom) ([]*subscriberInfo, error) {
+	infos := make([]*subscriberInfo, 0, len(rooms))
+	for idx := range rooms {
+		info, err := newSubscriberInfo(sub_id, &rooms[idx])
+		if err != nil {
+			return nil, fmt.Errorf("invalid subscription of room %d: %v", rooms[idx].RoomID, err)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/zerozwt/brelay/client"
+)
+
+// roomHistory keeps recent data msgs of a room in a ring buffer per cmd, accessed by
+// dmManager jobs only.
+type roomHistory struct {
+	cmds     map[string]*historyRing
+	next_seq uint64 // orders msgs of different cmds
+}
+
+type historyEntry struct {
+	seq  uint64
+	ts   int64 // unix timestamp in milliseconds
+	cmd  string
+	data []byte
+}
+
+type historyRing struct {
+	items []historyEntry
+	start int
+}
+
+func newRoomHistory() *roomHistory {
+	return &roomHistory{cmds: make(map[string]*historyRing)}
+}
+
+// add appends a msg to ring buffer of its cmd, the oldest one is overwritten if size exceeded
+func (h *roomHistory) add(size int, now time.Time, cmd string, data []byte) {
+	ring, ok := h.cmds[cmd]
+	if !ok {
+		ring = &historyRing{}
+		h.cmds[cmd] = ring
+	}
+	h.next_seq++
+	entry := historyEntry{seq: h.next_seq, ts: now.UnixNano() / int64(time.Millisecond), cmd: cmd, data: data}
+	if len(ring.items) < size {
+		ring.items = append(ring.items, entry)
+		return
+	}
+	ring.items[ring.start] = entry
+	ring.start = (ring.start + 1) % len(ring.items)
+}
+
+// backfill returns at most limit latest msgs accepted by info and not earlier than since, in
+// the order they were received. limit <= 0 means no limit.
+func (h *roomHistory) backfill(room_id int, info *subscriberInfo, limit int, since int64) []client.MsgSubscribeData {
+	entries := []historyEntry{}
+	for _, ring := range h.cmds {
+		for idx := range ring.items {
+			entry := &ring.items[(ring.start+idx)%len(ring.items)]
+			if entry.ts >= since && info.accept(entry.cmd, entry.data) {
+				entries = append(entries, *entry)
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	ret := make([]client.MsgSubscribeData, 0, len(entries))
+	for _, entry := range entries {
+		data := entry.data
+		if info.proj != nil {
+			data = info.proj.apply(data)
+		}
+		ret = append(ret, client.MsgSubscribeData{
+			RoomID:  room_id,
+			MsgType: client.MSG_TYPE_DATA,
+			Cmd:     entry.cmd,
+			Data:    data,
+			History: true,
+		})
+	}
+	return ret
+}
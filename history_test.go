@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zerozwt/brelay/client"
+)
+
+func TestRoomHistory(t *testing.T) {
+	h := newRoomHistory()
+	now := time.Now()
+	ms := func(d time.Duration) int64 { return now.Add(d).UnixNano() / int64(time.Millisecond) }
+
+	h.add(2, now, "DANMU_MSG", []byte(`{"cmd":"DANMU_MSG","n":1}`))
+	h.add(2, now.Add(time.Second), "SEND_GIFT", []byte(`{"cmd":"SEND_GIFT","n":2}`))
+	h.add(2, now.Add(2*time.Second), "DANMU_MSG", []byte(`{"cmd":"DANMU_MSG","n":3}`))
+	h.add(2, now.Add(3*time.Second), "DANMU_MSG", []byte(`{"cmd":"DANMU_MSG","n":4}`))
+
+	all, _ := newSubscriberInfo(1, &client.MsgSubscribeRoom{Cmds: []string{"*"}})
+	danmaku, _ := newSubscriberInfo(1, &client.MsgSubscribeRoom{Cmds: []string{"DANMU_MSG"}, Fields: []string{"n"}})
+
+	tests := []struct {
+		info   *subscriberInfo
+		limit  int
+		since  int64
+		expect []string
+	}{
+		{all, 0, 0, []string{`{"cmd":"SEND_GIFT","n":2}`, `{"cmd":"DANMU_MSG","n":3}`, `{"cmd":"DANMU_MSG","n":4}`}},
+		{all, 2, 0, []string{`{"cmd":"DANMU_MSG","n":3}`, `{"cmd":"DANMU_MSG","n":4}`}},
+		{all, 0, ms(2 * time.Second), []string{`{"cmd":"DANMU_MSG","n":3}`, `{"cmd":"DANMU_MSG","n":4}`}},
+		{danmaku, 1, 0, []string{`{"cmd":"DANMU_MSG","n":4}`}},
+	}
+	for idx, item := range tests {
+		msgs := h.backfill(100, item.info, item.limit, item.since)
+		if len(msgs) != len(item.expect) {
+			t.Errorf("case %d: expect %d msgs, got %d", idx, len(item.expect), len(msgs))
+			continue
+		}
+		for i, msg := range msgs {
+			if string(msg.Data) != item.expect[i] || !msg.History || msg.RoomID != 100 {
+				t.Errorf("case %d: msg %d got %+v, expect %s", idx, i, msg, item.expect[i])
+			}
+		}
+	}
+}
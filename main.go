@@ -3,6 +3,7 @@ package main
 import (
 	"crypto/tls"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
@@ -22,6 +23,23 @@ func initInbounds() bool {
 			logger().Printf("listen on [%s](%s) failed: %v", item.Name, item.Addr, err)
 			return false
 		}
+		switch item.Type {
+		case "", INBOUND_TYPE_TOYFRAME:
+		case INBOUND_TYPE_WEBSOCKET, INBOUND_TYPE_HTTP:
+			if lis = httpListener(&item, lis); lis == nil {
+				return false
+			}
+			name := item.Type + " inbound [" + item.Name + "]"
+			if item.Type == INBOUND_TYPE_WEBSOCKET {
+				serveHTTP(name, lis, webSocketHandler(&item))
+			} else {
+				serveHTTP(name, lis, sseHandler(&item))
+			}
+			continue
+		default:
+			logger().Printf("unknown type %s of listener %s", item.Type, item.Name)
+			return false
+		}
 		if len(item.Filters) == 0 {
 			gServer.AddListener(lis)
 			continue
@@ -52,6 +70,55 @@ func initInbounds() bool {
 	return true
 }
 
+// httpListener applies filters of a websocket or http inbound, only tls is supported
+func httpListener(item *InboundConfig, lis net.Listener) net.Listener {
+	for _, filter := range item.Filters {
+		if filter != "tls" {
+			logger().Printf("filter %s is not supported by %s listener %s", filter, item.Type, item.Name)
+			lis.Close()
+			return nil
+		}
+		cert, err := tls.LoadX509KeyPair(item.TlsPEMFile, item.TlsKeyFile)
+		if err != nil {
+			logger().Printf("create tls filter for listener %s failed: %v", item.Name, err)
+			lis.Close()
+			return nil
+		}
+		lis = tls.NewListener(lis, &tls.Config{Certificates: []tls.Certificate{cert}})
+	}
+	return lis
+}
+
+// serveHTTP serves http requests from lis until server shutdown
+func serveHTTP(name string, lis net.Listener, handler http.Handler) {
+	srv := &http.Server{Handler: handler}
+	wgAll.Add(1)
+	go func() {
+		defer wgAll.Done()
+		<-gServer.CloseChannel()
+		srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(lis); err != nil && err != http.ErrServerClosed {
+			logger().Printf("%s stopped: %v", name, err)
+		}
+	}()
+}
+
+func registerHandlers() {
+	gServer.Register("login", loginHandler)
+	gServer.Register("resume", resumeHandler)
+	gServer.Register("logout", logoutHandler)
+	gServer.Register("subscribe", subscribeHandler)
+	gServer.Register("subscribe_add", subscribeAddHandler)
+	gServer.Register("subscribe_remove", subscribeRemoveHandler)
+	gServer.Register("list_subscriptions", listSubscriptionsHandler)
+	gServer.Register("admin_list_subscribers", adminListSubscribersHandler)
+	gServer.Register("admin_list_rooms", adminListRoomsHandler)
+	gServer.Register("admin_kick", adminKickHandler)
+	gServer.Register("admin_room", adminRoomHandler)
+}
+
 func main() {
 	// load config
 	if !initConf() {
@@ -69,6 +136,15 @@ func main() {
 	if !initInbounds() {
 		return
 	}
+	if !initReplays() {
+		return
+	}
+	if !initRecorder() {
+		return
+	}
+	if !initMetrics() {
+		return
+	}
 
 	logger().Printf("bilibili live danmaku relay server start.....")
 
@@ -81,9 +157,12 @@ func main() {
 	}()
 
 	// register handlers
-	gServer.Register("login", loginHandler)
-	gServer.Register("logout", logoutHandler)
-	gServer.Register("subscribe", subscribeHandler)
+	registerHandlers()
+
+	// start sinks
+	if !initWebhooks() || !initRedisSinks() {
+		return
+	}
 
 	// start server
 	gServer.Run()
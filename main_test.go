@@ -1,19 +1,36 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"io"
+	"io/ioutil"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	dm "github.com/zerozwt/BLiveDanmaku"
 	"github.com/zerozwt/brelay/client"
+	"github.com/zerozwt/brelay/client/events"
+	"github.com/zerozwt/brelay/dmmock"
+	"github.com/zerozwt/brelay/respmock"
+	"github.com/zerozwt/toyframe"
 	"github.com/zerozwt/toyframe/dialer"
 )
 
 var test_init int32
 var test_dial dialer.DialFunc = dialer.B(net.Dial).WithBitReverse().WithMultiplex().WithBrotli().Build()
+var test_upstream *dmmock.Server = dmmock.NewServer()
+var test_record_dir string
 
 func testInit(t *testing.T) bool {
 	if !atomic.CompareAndSwapInt32(&test_init, 0, 1) {
@@ -26,22 +43,994 @@ func testInit(t *testing.T) bool {
 			Addr:    "localhost:6789",
 			Filters: []string{"reverse", "multiplex", "brotli"},
 		},
+		{
+			Name: "test_ws",
+			Type: INBOUND_TYPE_WEBSOCKET,
+			Addr: "localhost:6790",
+		},
+		{
+			Name:   "test_sse",
+			Type:   INBOUND_TYPE_HTTP,
+			Addr:   "localhost:6791",
+			Tokens: []string{"test_token"},
+		},
+	}
+	gConf.RoomIdleTimeout = 200 * time.Millisecond
+	gConf.ResumeTimeout = 500 * time.Millisecond
+	gConf.AdminKey = "test_admin_key"
+	gClientMgr.upstream = testUpstream
+	if !testInitReplay(t) {
+		return false
 	}
 
 	if !initInbounds() {
 		t.Errorf("init inbound error")
 		return false
 	}
-	gServer.Register("login", loginHandler)
-	gServer.Register("logout", logoutHandler)
-	gServer.Register("subscribe", subscribeHandler)
+	registerHandlers()
 
 	go gServer.Run()
 	return true
 }
 
+func testUpstream(room_id int, handler *upstreamHandler) (upstreamConn, error) {
+	conn, err := test_upstream.Dial(room_id, dmmock.Handler{
+		OnMsg:             func(conn *dmmock.Conn, data []byte) { handler.OnMsg(conn, data) },
+		OnLiveStateChange: func(conn *dmmock.Conn, cmd string) { handler.OnLiveStateChange(conn, cmd) },
+		OnDisconnect:      func(conn *dmmock.Conn, err error) { handler.OnDisconnect(conn, err) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// testInitReplay records a short script of room 7777 and replays it as virtual room 9001
+func testInitReplay(t *testing.T) bool {
+	var err error
+	if test_record_dir, err = ioutil.TempDir("", "brelay_replay"); err != nil {
+		t.Errorf("create record dir failed: %v", err)
+		return false
+	}
+
+	r := &recorder{conf: RecorderConfig{Dir: test_record_dir}, files: make(map[int]*recordFile)}
+	ts := time.Now()
+	for idx, cmd := range []string{dm.CMD_LIVE, dm.CMD_DANMU_MSG, dm.CMD_SEND_GIFT} {
+		data := []byte(`{"cmd":"` + cmd + `","roomid":7777}`)
+		r.write(&recordItem{ts: ts.Add(time.Duration(idx) * time.Second), room_id: 7777, cmd: cmd, data: data})
+	}
+	r.closeAll()
+
+	gConf.Replays = []ReplayConfig{{RoomID: 9001, Files: []string{test_record_dir + "/7777/*.jsonl"}, Speed: 20, Loop: true}}
+	if !initReplays() {
+		t.Errorf("init replays failed")
+		return false
+	}
+	return true
+}
+
+func testReplay(t *testing.T) {
+	sess := newTestSession("replay", t)
+	defer sess.close()
+
+	if err := sess.client.Subscribe([]client.MsgSubscribeRoom{{RoomID: 9001, Cmds: []string{dm.CMD_SEND_GIFT}}}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	if !sess.waitFor(time.Second, msgTypeOf(9001, client.MSG_TYPE_WS_CONNECT)) {
+		t.Fatalf("replay room not connected")
+	}
+
+	// recorded msgs are replayed in a loop
+	gifts := 0
+	sess.waitFor(2*time.Second, func(msg *client.MsgSubscribeData) bool {
+		if msg.RoomID == 9001 && msg.Cmd == dm.CMD_SEND_GIFT {
+			gifts++
+		}
+		return gifts >= 2
+	})
+	if gifts < 2 {
+		t.Errorf("expect replayed gifts in a loop, got %d", gifts)
+	}
+	if gClientMgr.RoomState(9001) != DM_CLIENT_STATE_CONNECTED {
+		t.Errorf("replay room not connected")
+	}
+}
+
+func testWebSocket(t *testing.T) {
+	conn, _, err := websocket.DefaultDialer.Dial("ws://localhost:6790/ws", nil)
+	if err != nil {
+		t.Fatalf("dial websocket failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	call := func(method string, params string) map[string]interface{} {
+		req := `{"method":"` + method + `","req":1,"params":` + params + `}`
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+			t.Fatalf("send %s failed: %v", method, err)
+		}
+		for {
+			ret := map[string]interface{}{}
+			if err := conn.ReadJSON(&ret); err != nil {
+				t.Fatalf("read %s response failed: %v", method, err)
+			}
+			if ret["type"] == "response" {
+				if ret["ok"] != true {
+					t.Fatalf("%s failed: %v", method, ret["msg"])
+				}
+				return ret
+			}
+		}
+	}
+
+	call("login", `{"id":"ws","flush_ms":100}`)
+	call("subscribe", `{"rooms":[{"room":7777,"cmds":["DANMU_MSG"],"fields":["info.1"]}]}`)
+
+	// batches carry msg data as json
+	for {
+		batch := struct {
+			Type string
+			Msgs []struct {
+				Cmd  string
+				Data struct{ Info []string }
+			}
+		}{}
+		if err := conn.ReadJSON(&batch); err != nil {
+			t.Fatalf("read batch failed: %v", err)
+		}
+		if batch.Type == "batch" && len(batch.Msgs) > 0 && batch.Msgs[len(batch.Msgs)-1].Cmd == dm.CMD_DANMU_MSG {
+			if info := batch.Msgs[len(batch.Msgs)-1].Data.Info; len(info) != 2 || info[1] != "hello" {
+				t.Errorf("unexpected projected danmaku %v", info)
+			}
+			break
+		}
+	}
+
+	rsp := call("list_subscriptions", `{}`)
+	if rooms := rsp["result"].(map[string]interface{})["rooms"].([]interface{}); len(rooms) != 1 {
+		t.Errorf("expect 1 subscription, got %d", len(rooms))
+	}
+	call("logout", `{}`)
+}
+
+func testSSE(t *testing.T) {
+	rsp, err := http.Get("http://localhost:6791/rooms/7777/events?cmds=DANMU_MSG")
+	if err != nil {
+		t.Fatalf("request sse failed: %v", err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expect status %d without token, got %d", http.StatusUnauthorized, rsp.StatusCode)
+	}
+	rsp, err = http.Get("http://localhost:6791/rooms/7777/events?cmds=DANMU_MSG&token=brelay_key")
+	if err != nil {
+		t.Fatalf("request sse failed: %v", err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expect status %d with default login key, got %d", http.StatusUnauthorized, rsp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:6791/rooms/7777/events?cmds=DANMU_MSG&flush_ms=100", nil)
+	req.Header.Set("Authorization", "Bearer test_token")
+	rsp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request sse failed: %v", err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expect status %d, got %d", http.StatusOK, rsp.StatusCode)
+	}
+
+	events := make(chan string, 16)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(rsp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "event: ") {
+				events <- strings.TrimPrefix(line, "event: ")
+			}
+		}
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event == dm.CMD_SEND_GIFT {
+				t.Fatalf("recieved unsubscribed cmd %s", event)
+			}
+			if event == dm.CMD_DANMU_MSG {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("no danmaku event recieved")
+		}
+	}
+}
+
+func testWebhook(t *testing.T) {
+	posted := make(chan []byte, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		sign := r.Header.Get("X-BRelay-Signature")
+		if sign != "sha256="+webhookSignature("test_secret", r.Header.Get("X-BRelay-Timestamp"), body) {
+			t.Errorf("invalid webhook signature %s", sign)
+		}
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		select {
+		case posted <- body:
+		default:
+		}
+	}))
+	defer srv.Close()
+
+	dead_letter := test_record_dir + "/webhook.dead.jsonl"
+	rooms := []SinkRoomConfig{{RoomID: 7777, Cmds: []string{dm.CMD_DANMU_MSG}}}
+	for _, path := range []string{"/up", "/down"} {
+		sink := &webhookSink{
+			conf: WebhookConfig{
+				Name:   path,
+				URL:    srv.URL + path,
+				Secret: "test_secret",
+				SinkRetryConfig: SinkRetryConfig{
+					MaxRetries:     1,
+					RetryInterval:  10 * time.Millisecond,
+					DeadLetterFile: dead_letter,
+				},
+			},
+			client: srv.Client(),
+		}
+		if !startSink("webhook "+path, rooms, 100*time.Millisecond, sink.deliver) {
+			t.Fatalf("start webhook sink failed")
+		}
+	}
+
+	deadline := time.After(2 * time.Second)
+	for danmaku := false; !danmaku; {
+		select {
+		case body := <-posted:
+			batch := struct{ Msgs []struct{ Cmd string } }{}
+			if err := json.Unmarshal(body, &batch); err != nil {
+				t.Fatalf("unexpected webhook body %s", string(body))
+			}
+			for _, msg := range batch.Msgs {
+				danmaku = danmaku || msg.Cmd == dm.CMD_DANMU_MSG
+			}
+		case <-deadline:
+			t.Fatalf("no danmaku posted")
+		}
+	}
+
+	// batches rejected by target are written to dead-letter file
+	time.Sleep(200 * time.Millisecond)
+	if data, err := ioutil.ReadFile(dead_letter); err != nil || !strings.Contains(string(data), `"sink":"/down"`) {
+		t.Errorf("no dead letter written: %v", err)
+	}
+}
+
+func testRedisSink(t *testing.T) {
+	srv, err := respmock.NewServer("localhost:0", "test_password", 64)
+	if err != nil {
+		t.Fatalf("start resp server failed: %v", err)
+	}
+	defer srv.Close()
+
+	conf := RedisSinkConfig{
+		Name:     "test",
+		Addr:     srv.Addr(),
+		Password: "test_password",
+		Prefix:   defaultRedisPrefix,
+		Timeout:  time.Second,
+		SinkRetryConfig: SinkRetryConfig{
+			MaxRetries:       20,
+			RetryInterval:    10 * time.Millisecond,
+			MaxRetryInterval: 10 * time.Millisecond,
+		},
+	}
+	sink := &redisSink{conf: conf}
+	if !startSink("redis test", []SinkRoomConfig{{RoomID: 7777, Cmds: []string{dm.CMD_DANMU_MSG}}}, 100*time.Millisecond, sink.deliver) {
+		t.Fatalf("start redis sink failed")
+	}
+	defer stopSink("sink redis test")
+
+	// msgs are published again after connection dropped
+	for round := 0; round < 2; round++ {
+		deadline := time.After(2 * time.Second)
+		for danmaku := false; !danmaku; {
+			select {
+			case msg := <-srv.Messages():
+				if msg.Channel == "brelay:7777:DANMU_MSG" {
+					danmaku = strings.Contains(msg.Payload, "hello")
+				} else if msg.Channel != "brelay:7777:ws_connect" {
+					t.Errorf("unexpected msg published to %s: %s", msg.Channel, msg.Payload)
+				}
+			case <-deadline:
+				t.Fatalf("no danmaku published in round %d", round)
+			}
+		}
+		srv.DropConns()
+	}
+}
+
+func testMetrics(t *testing.T) {
+	sess := newTestSession("metrics", t)
+	defer sess.close()
+
+	rsp := httptest.NewRecorder()
+	metricsHandler(rsp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rsp.Body.String()
+	for _, expect := range []string{
+		"# TYPE brelay_room_msgs_total counter\n",
+		`brelay_room_msgs_total{room="7777",cmd="DANMU_MSG"} `,
+		`brelay_upstream_rooms{state="connected"} `,
+		`brelay_subscribers{state="attached"} `,
+		`brelay_subscriber_cache_msgs{subscriber="`,
+		"brelay_upstream_disconnects_total ",
+	} {
+		if !strings.Contains(body, expect) {
+			t.Errorf("metric %q not found", expect)
+		}
+	}
+}
+
+func testAdmin(t *testing.T) {
+	room := test_upstream.Room(1001)
+	sess := newTestSession("admin_target", t)
+	defer sess.close()
+	if err := sess.client.Subscribe([]client.MsgSubscribeRoom{{RoomID: 1001, Cmds: []string{"*"}}}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	if !sess.waitFor(time.Second, msgTypeOf(1001, client.MSG_TYPE_WS_CONNECT)) {
+		t.Fatalf("room not connected")
+	}
+
+	if _, err := client.NewAdminClient("wrong_key", "tcp", "localhost:6789", test_dial, nil).ListRooms(); err == nil {
+		t.Errorf("admin request with wrong key succeeded")
+	}
+	admin := client.NewAdminClient("test_admin_key", "tcp", "localhost:6789", test_dial, nil)
+
+	subscribers, err := admin.ListSubscribers()
+	if err != nil {
+		t.Fatalf("list subscribers failed: %v", err)
+	}
+	var target *client.MsgAdminSubscriber
+	for idx := range subscribers {
+		if subscribers[idx].Name == "admin_target" {
+			target = &subscribers[idx]
+		}
+	}
+	if target == nil || len(target.Addr) == 0 || len(target.Rooms) != 1 || target.Rooms[0] != 1001 {
+		t.Fatalf("subscriber admin_target not listed: %+v", target)
+	}
+
+	rooms, err := admin.ListRooms()
+	if err != nil {
+		t.Fatalf("list rooms failed: %v", err)
+	}
+	found := false
+	for _, item := range rooms {
+		if item.RoomID == 1001 {
+			found = item.State == client.ROOM_STATE_CONNECTED && item.Subscribers == 1 && len(item.Info) > 0
+		}
+	}
+	if !found {
+		t.Errorf("room 1001 not listed as connected: %+v", rooms)
+	}
+
+	// force reconnect
+	if err := admin.ReconnectRoom(1001); err != nil {
+		t.Fatalf("reconnect room failed: %v", err)
+	}
+	if !sess.waitFor(time.Second, msgTypeOf(1001, client.MSG_TYPE_WS_CONNECT)) || room.Dials() != 2 {
+		t.Errorf("room not reconnected, dials %d", room.Dials())
+	}
+
+	// close room drops subscriptions
+	if err := admin.CloseRoom(1001); err != nil {
+		t.Fatalf("close room failed: %v", err)
+	}
+	if !sess.waitFor(time.Second, msgTypeOf(1001, client.MSG_TYPE_WS_DISCONNECT)) || room.Conns() != 0 {
+		t.Errorf("room not closed")
+	}
+	if subs, err := sess.client.Subscriptions(); err != nil || len(subs) != 0 {
+		t.Errorf("subscriptions not dropped: %v %v", subs, err)
+	}
+	if err := admin.CloseRoom(1001); err == nil {
+		t.Errorf("close room not connected succeeded")
+	}
+
+	// sink subscribers are never kicked
+	sink_id, err := gDanmaku.AllocSubscriberID(newMailbox(), &subscriberConf{name: "sink admin_test"})
+	if err != nil {
+		t.Fatalf("alloc sink subscriber failed: %v", err)
+	}
+	defer gDanmaku.Logout(sink_id)
+	if err := admin.Kick(sink_id); err == nil {
+		t.Errorf("kick sink subscriber succeeded")
+	}
+	subscribers, _ = admin.ListSubscribers()
+	found = false
+	for _, item := range subscribers {
+		found = found || item.SubscriberID == sink_id
+	}
+	if !found {
+		t.Errorf("sink subscriber logged out by kick")
+	}
+
+	// kick closes login stream
+	if err := admin.Kick(target.SubscriberID); err != nil {
+		t.Fatalf("kick failed: %v", err)
+	}
+	if sess.waitFor(time.Second, func(*client.MsgSubscribeData) bool { return false }) {
+		t.Errorf("login stream not closed after kicked")
+	}
+	select {
+	case _, ok := <-sess.msgs:
+		if ok {
+			t.Errorf("login stream not closed after kicked")
+		}
+	default:
+		t.Errorf("login stream not closed after kicked")
+	}
+	if err := admin.Kick(target.SubscriberID); err == nil {
+		t.Errorf("kick unknown subscriber succeeded")
+	}
+}
+
+func testManagedSession(t *testing.T) {
+	sess := client.NewSession("managed", "tcp", "localhost:6789", test_dial)
+	sess.Client().SetFlushInterval(100 * time.Millisecond)
+	sess.SetBackoff(50*time.Millisecond, 200*time.Millisecond)
+	if err := sess.Subscribe([]client.MsgSubscribeRoom{{RoomID: 7777, Cmds: []string{"DANMU_MSG"}}}); err != nil {
+		t.Fatalf("subscribe before start failed: %v", err)
+	}
+	sess.Start()
+	defer sess.Close()
+
+	waitConnected := func() bool {
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case event := <-sess.Events():
+				if event.State == client.SESSION_STATE_CONNECTED && event.Err == nil {
+					return true
+				}
+			case <-timeout:
+				return false
+			}
+		}
+	}
+	waitDanmaku := func() bool {
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case msg := <-sess.Messages():
+				if msg.RoomID == 7777 && msg.Cmd == dm.CMD_DANMU_MSG {
+					return true
+				}
+			case <-timeout:
+				return false
+			}
+		}
+	}
+	if !waitConnected() || !waitDanmaku() {
+		t.Fatalf("session not connected")
+	}
+
+	// kicked subscriber could not be resumed, session logs in again and re-subscribes
+	admin := client.NewAdminClient("test_admin_key", "tcp", "localhost:6789", test_dial, nil)
+	subscribers, err := admin.ListSubscribers()
+	if err != nil {
+		t.Fatalf("list subscribers failed: %v", err)
+	}
+	for _, item := range subscribers {
+		if item.Name == "managed" {
+			if err := admin.Kick(item.SubscriberID); err != nil {
+				t.Fatalf("kick failed: %v", err)
+			}
+		}
+	}
+	if !waitConnected() {
+		t.Fatalf("session not reconnected")
+	}
+	for len(sess.Messages()) > 0 {
+		<-sess.Messages()
+	}
+	if !waitDanmaku() {
+		t.Errorf("session not re-subscribed")
+	}
+	if rooms := sess.Rooms(); len(rooms) != 1 || rooms[0].RoomID != 7777 {
+		t.Errorf("desired rooms changed: %+v", rooms)
+	}
+
+	// closing a session never started
+	idle := client.NewSession("managed_idle", "tcp", "localhost:6789", test_dial)
+	if err := idle.Close(); err != nil {
+		t.Errorf("close unstarted session failed: %v", err)
+	}
+	if _, ok := <-idle.Messages(); ok {
+		t.Errorf("messages channel of unstarted session not closed")
+	}
+}
+
+func testSeqAck(t *testing.T) {
+	room := test_upstream.Room(1003)
+	c := client.NewBRelayClient("seq_ack", "tcp", "localhost:6789", test_dial, nil)
+	c.SetAckMode(true)
+	c.SetFlushInterval(50 * time.Millisecond)
+	stream, err := c.Login()
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	defer c.Logout()
+	if err := c.Subscribe([]client.MsgSubscribeRoom{{RoomID: 1003, Cmds: []string{"*"}}}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	readUntil := func(what string, match func(msg *client.MsgSubscribeData) bool) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		for {
+			last := c.LastSeq()
+			msgs, gap, err := c.ReadMessagesChecked(ctx, stream)
+			if err != nil {
+				t.Fatalf("%s not received: %v", what, err)
+			}
+			if gap != nil || c.LastSeq() != last+1 {
+				t.Errorf("unexpected seq %d after %d, gap %v", c.LastSeq(), last, gap)
+			}
+			for idx := range msgs {
+				if match(&msgs[idx]) {
+					return
+				}
+			}
+		}
+	}
+	readDanmaku := func(text string) {
+		t.Helper()
+		readUntil("danmaku "+text, func(msg *client.MsgSubscribeData) bool {
+			return msg.Cmd == dm.CMD_DANMU_MSG && strings.Contains(string(msg.Data), text)
+		})
+	}
+	unacked := func() int {
+		time.Sleep(100 * time.Millisecond)
+		subscribers, _ := gDanmaku.AdminSubscribers()
+		for _, item := range subscribers {
+			if item.Name == "seq_ack" {
+				return item.Unacked
+			}
+		}
+		return -1
+	}
+
+	// batches are numbered one by one and retained until acked
+	readUntil("room state", msgTypeOf(1003, client.MSG_TYPE_WS_CONNECT))
+	room.Danmaku(1, "user1", "first")
+	readDanmaku("first")
+	if count := unacked(); count <= 0 {
+		t.Errorf("no batch retained before ack")
+	}
+	if err := c.Ack(stream); err != nil {
+		t.Fatalf("ack failed: %v", err)
+	}
+	if count := unacked(); count != 0 {
+		t.Errorf("%d batches retained after ack", count)
+	}
+
+	// batches not received are redelivered on resume
+	room.Danmaku(1, "user1", "second")
+	if count := unacked(); count <= 0 {
+		t.Errorf("batch not sent before stream closed")
+	}
+	stream.Close()
+	time.Sleep(100 * time.Millisecond)
+	if stream, err = c.Resume(); err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	defer stream.Close()
+	readDanmaku("second")
+
+	// gaps of seq are reported, duplicated batches are skipped
+	local, remote := net.Pipe()
+	defer local.Close()
+	go func() {
+		defer remote.Close()
+		out := toyframe.NewContext(remote)
+		for _, seq := range []uint64{1, 2, 2, 4} {
+			out.WriteObj(&client.MsgSubscribeBatch{Seq: seq, Msgs: []client.MsgSubscribeData{{RoomID: 1003}}})
+		}
+	}()
+	gap_client := client.NewBRelayClient("seq_gap", "tcp", "localhost:6789", test_dial, nil)
+	for _, expect := range []uint64{1, 2, 4} {
+		_, gap, err := gap_client.ReadMessagesChecked(context.Background(), toyframe.NewContext(local))
+		if err != nil || gap_client.LastSeq() != expect {
+			t.Fatalf("read batch %d failed: %v, last seq %d", expect, err, gap_client.LastSeq())
+		}
+		if (expect == 4) != (gap != nil) || (gap != nil && (gap.Last != 2 || gap.Current != 4)) {
+			t.Errorf("unexpected gap reading batch %d: %v", expect, gap)
+		}
+	}
+}
+
+func testSubscribeRemove(t *testing.T) {
+	room := test_upstream.Room(1005)
+	sess := newTestSession("subscribe_remove", t)
+	defer sess.close()
+	if err := sess.client.Subscribe([]client.MsgSubscribeRoom{{RoomID: 1005, Cmds: []string{dm.CMD_DANMU_MSG, dm.CMD_SEND_GIFT}}}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	if !sess.waitFor(time.Second, msgTypeOf(1005, client.MSG_TYPE_WS_CONNECT)) {
+		t.Fatalf("room not connected")
+	}
+
+	// removing one cmd keeps the other one delivered
+	if err := sess.client.SubscribeRemove([]client.MsgSubscribeRoom{{RoomID: 1005, Cmds: []string{dm.CMD_DANMU_MSG}}}); err != nil {
+		t.Fatalf("subscribe remove failed: %v", err)
+	}
+	room.Danmaku(1, "user1", "removed")
+	room.Gift(1, "user1", "gift", 1, 100)
+	danmaku := false
+	if !sess.waitFor(time.Second, func(msg *client.MsgSubscribeData) bool {
+		danmaku = danmaku || msg.Cmd == dm.CMD_DANMU_MSG
+		return msg.Cmd == dm.CMD_SEND_GIFT
+	}) {
+		t.Errorf("gift of remaining cmd not received")
+	}
+	if danmaku {
+		t.Errorf("danmaku of removed cmd received")
+	}
+	subs, err := sess.client.Subscriptions()
+	if err != nil || len(subs) != 1 || len(subs[0].Cmds) != 1 || subs[0].Cmds[0] != dm.CMD_SEND_GIFT {
+		t.Errorf("unexpected subscriptions after removing a cmd: %+v %v", subs, err)
+	}
+
+	// removing the last cmd unsubscribes the room
+	if err := sess.client.SubscribeRemove([]client.MsgSubscribeRoom{{RoomID: 1005, Cmds: []string{dm.CMD_SEND_GIFT}}}); err != nil {
+		t.Fatalf("subscribe remove failed: %v", err)
+	}
+	if subs, err := sess.client.Subscriptions(); err != nil || len(subs) != 0 {
+		t.Errorf("room not unsubscribed after removing all cmds: %+v %v", subs, err)
+	}
+}
+
+func testListSubscriptions(t *testing.T) {
+	test_upstream.Room(1006)
+	test_upstream.Room(1007).FailDials(1)
+
+	sess := newTestSession("list_subscriptions", t)
+	defer sess.close()
+	if err := sess.client.Subscribe([]client.MsgSubscribeRoom{
+		{RoomID: 1007, Cmds: []string{"*"}},
+		{RoomID: 1006, Cmds: []string{dm.CMD_SEND_GIFT, dm.CMD_DANMU_MSG}, Fields: []string{"uname"}},
+	}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	connected, failed := false, false
+	if !sess.waitFor(time.Second, func(msg *client.MsgSubscribeData) bool {
+		connected = connected || msgTypeOf(1006, client.MSG_TYPE_WS_CONNECT)(msg)
+		failed = failed || msgTypeOf(1007, client.MSG_TYPE_ROOM_CONN_FAIL)(msg)
+		return connected && failed
+	}) {
+		t.Fatalf("room states not received, connected %v failed %v", connected, failed)
+	}
+
+	subs, err := sess.client.Subscriptions()
+	if err != nil || len(subs) != 2 {
+		t.Fatalf("unexpected subscriptions: %+v %v", subs, err)
+	}
+	cmds := append([]string{}, subs[0].Cmds...)
+	sort.Strings(cmds)
+	if subs[0].RoomID != 1006 || strings.Join(cmds, ",") != dm.CMD_DANMU_MSG+","+dm.CMD_SEND_GIFT ||
+		len(subs[0].Fields) != 1 || subs[0].Fields[0] != "uname" || subs[0].State != client.ROOM_STATE_CONNECTED {
+		t.Errorf("unexpected subscription of connected room: %+v", subs[0])
+	}
+	if subs[1].RoomID != 1007 || len(subs[1].Cmds) != 1 || subs[1].Cmds[0] != "*" || subs[1].State != client.ROOM_STATE_INIT {
+		t.Errorf("unexpected subscription of room failing to connect: %+v", subs[1])
+	}
+}
+
+func testResume(t *testing.T) {
+	room := test_upstream.Room(1004)
+	c := client.NewBRelayClient("resume", "tcp", "localhost:6789", test_dial, nil)
+	c.SetFlushInterval(50 * time.Millisecond)
+	stream, err := c.Login()
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if err := c.Subscribe([]client.MsgSubscribeRoom{{RoomID: 1004, Cmds: []string{dm.CMD_DANMU_MSG}}}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for connected := false; !connected; {
+		msgs, err := c.ReadMessagesContext(ctx, stream)
+		if err != nil {
+			t.Fatalf("room not connected: %v", err)
+		}
+		for idx := range msgs {
+			connected = connected || msgTypeOf(1004, client.MSG_TYPE_WS_CONNECT)(&msgs[idx])
+		}
+	}
+
+	// msgs cached while detached are delivered in order after resumed
+	stream.Close()
+	time.Sleep(100 * time.Millisecond)
+	texts := []string{"one", "two", "three"}
+	for _, text := range texts {
+		room.Danmaku(1, "user1", text)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if stream, err = c.Resume(); err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	received := []string{}
+	for len(received) < len(texts) {
+		msgs, err := c.ReadMessagesContext(ctx, stream)
+		if err != nil {
+			t.Fatalf("cached msgs not received, got %v: %v", received, err)
+		}
+		for _, msg := range msgs {
+			if obj, err := events.DecodeDanmaku(msg.Data); err == nil {
+				received = append(received, obj.Content)
+			}
+		}
+	}
+	for idx := range texts {
+		if received[idx] != texts[idx] {
+			t.Errorf("cached msgs out of order: %v", received)
+			break
+		}
+	}
+
+	// subscriber logged out if not resumed in time
+	stream.Close()
+	time.Sleep(gConf.ResumeTimeout + 200*time.Millisecond)
+	if stream, err = c.Resume(); err == nil {
+		stream.Close()
+		t.Errorf("resume after timeout succeeded")
+	}
+}
+
+func testClientContext(t *testing.T) {
+	c := client.NewBRelayClient("context", "tcp", "localhost:6789", test_dial, nil)
+	c.SetFlushInterval(100 * time.Millisecond)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.LoginContext(cancelled); err != context.Canceled {
+		t.Errorf("login with cancelled context returns %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	stream, err := c.LoginContext(ctx)
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	defer c.Logout()
+	defer stream.Close()
+	if err := c.SubscribeContext(ctx, []client.MsgSubscribeRoom{{RoomID: 1002, Cmds: []string{"*"}}}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	if msgs, err := c.ReadMessagesContext(ctx, stream); err != nil || len(msgs) == 0 {
+		t.Fatalf("read msgs failed: %v", err)
+	}
+
+	// no more msgs from the room
+	short, cancel_short := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel_short()
+	if _, err := c.ReadMessagesContext(short, stream); err != context.DeadlineExceeded {
+		t.Errorf("read msgs with deadline returns %v", err)
+	}
+}
+
+func testClientHandlers(t *testing.T) {
+	c := client.NewBRelayClient("handlers", "tcp", "localhost:6789", test_dial, nil)
+	c.SetFlushInterval(100 * time.Millisecond)
+
+	connected, danmaku, after_panic, panics := 0, 0, 0, 0 // handlers are called in Run
+	c.OnConnect(7777, func(room_id int, info *events.RoomInfo) { connected++ })
+	c.OnDanmaku(client.ALL_ROOMS, func(room_id int, msg *events.Danmaku) { panic("handler panic") })
+	c.OnDanmaku(7777, func(room_id int, msg *events.Danmaku) {
+		if msg.Content == "hello" {
+			danmaku++
+		}
+	})
+	c.OnCmd(7777, dm.CMD_DANMU_MSG, func(msg *client.MsgSubscribeData) { after_panic++ })
+	c.OnGift(7777, func(room_id int, msg *events.Gift) { t.Errorf("received unsubscribed gift") })
+	c.OnError(func(msg *client.MsgSubscribeData, err error) {
+		if _, ok := err.(*client.PanicError); ok {
+			panics++
+		}
+	})
+
+	stream, err := c.Login()
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	defer c.Logout()
+	defer stream.Close()
+	if err := c.Subscribe([]client.MsgSubscribeRoom{{RoomID: 7777, Cmds: []string{dm.CMD_DANMU_MSG}}}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := c.Run(ctx, stream); err != context.DeadlineExceeded {
+		t.Errorf("run returns %v", err)
+	}
+	if connected != 1 || danmaku == 0 {
+		t.Errorf("handlers not called: connected %d danmaku %d", connected, danmaku)
+	}
+	if panics == 0 || after_panic != panics {
+		t.Errorf("handler panic not isolated: panics %d after %d", panics, after_panic)
+	}
+}
+
+func testFailover(t *testing.T) {
+	// a proxy in front of the server simulates a relay node going down
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	var conns []net.Conn
+	var lock sync.Mutex
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			upstream, err := net.Dial("tcp", "localhost:6789")
+			if err != nil {
+				conn.Close()
+				continue
+			}
+			lock.Lock()
+			conns = append(conns, conn, upstream)
+			lock.Unlock()
+			go io.Copy(upstream, conn)
+			go io.Copy(conn, upstream)
+		}
+	}()
+	proxyDown := func() {
+		lis.Close()
+		lock.Lock()
+		defer lock.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	defer proxyDown()
+
+	c, err := client.NewBRelayClientWithEndpoints("failover", []client.Endpoint{
+		{Network: "tcp", Address: lis.Addr().String()},
+		{Network: "tcp", Address: "localhost:6789"},
+	}, test_dial, nil)
+	if err != nil {
+		t.Fatalf("create client failed: %v", err)
+	}
+	c.SetFlushInterval(100 * time.Millisecond)
+
+	stream, err := c.Login()
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if err := c.Subscribe([]client.MsgSubscribeRoom{{RoomID: 7777, Cmds: []string{dm.CMD_DANMU_MSG}}}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	if err := c.SubscribeAdd([]client.MsgSubscribeRoom{{RoomID: 7777, Cmds: []string{dm.CMD_SEND_GIFT}}}); err != nil {
+		t.Fatalf("subscribe add failed: %v", err)
+	}
+	if endpoints := c.Endpoints(); !endpoints[0].Current {
+		t.Fatalf("login not on first endpoint: %+v", endpoints)
+	}
+
+	proxyDown()
+	for {
+		if _, err := c.ReadMessages(stream); err != nil {
+			break
+		}
+	}
+	stream.Close()
+	if _, err := c.Resume(); err == nil {
+		t.Fatalf("resume through a down endpoint succeeded")
+	}
+
+	// login fails over to the second endpoint with subscriptions re-applied
+	stream, err = c.Login()
+	if err != nil {
+		t.Fatalf("login after failing over failed: %v", err)
+	}
+	defer c.Logout()
+	defer stream.Close()
+	endpoints := c.Endpoints()
+	if !endpoints[1].Current || endpoints[0].Healthy {
+		t.Errorf("unexpected endpoint status: %+v", endpoints)
+	}
+	subs, err := c.Subscriptions()
+	if err != nil || len(subs) != 1 || subs[0].RoomID != 7777 || len(subs[0].Cmds) != 2 {
+		t.Fatalf("subscriptions not re-applied: %+v %v", subs, err)
+	}
+}
+
+// testSession is a logged in client collecting received msgs
+type testSession struct {
+	client *client.Client
+	ctx    *toyframe.Context
+	msgs   chan client.MsgSubscribeData
+}
+
+// stopSink logs out the internal subscriber of a sink started by a test
+func stopSink(name string) {
+	subscribers, _ := gDanmaku.AdminSubscribers()
+	for _, item := range subscribers {
+		if item.Name == name {
+			gDanmaku.Logout(item.SubscriberID)
+		}
+	}
+}
+
+func newTestSession(name string, t *testing.T) *testSession {
+	relay_client := client.NewBRelayClient(name, "tcp", "localhost:6789", test_dial, nil)
+	relay_client.SetFlushInterval(100 * time.Millisecond)
+	ctx, err := relay_client.Login()
+	if err != nil {
+		t.Fatalf("client %s login failed: %v", name, err)
+	}
+	ret := &testSession{client: relay_client, ctx: ctx, msgs: make(chan client.MsgSubscribeData, 1024)}
+	go func() {
+		defer close(ret.msgs)
+		for {
+			arr, err := relay_client.ReadMessages(ctx)
+			if err != nil {
+				return
+			}
+			for _, item := range arr {
+				ret.msgs <- item
+			}
+		}
+	}()
+	return ret
+}
+
+// waitFor reads msgs until match returns true, returns false on timeout or stream closed
+func (s *testSession) waitFor(timeout time.Duration, match func(msg *client.MsgSubscribeData) bool) bool {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case msg, ok := <-s.msgs:
+			if !ok {
+				return false
+			}
+			if match(&msg) {
+				return true
+			}
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+func (s *testSession) close() {
+	s.client.Logout()
+	s.ctx.Close()
+}
+
+func msgTypeOf(room_id int, msg_type byte) func(msg *client.MsgSubscribeData) bool {
+	return func(msg *client.MsgSubscribeData) bool {
+		return msg.RoomID == room_id && msg.MsgType == msg_type
+	}
+}
+
 func testClient(name string, life time.Duration, t *testing.T) {
 	relay_client := client.NewBRelayClient(name, "tcp", "localhost:6789", test_dial, nil)
+	relay_client.SetFlushInterval(100 * time.Millisecond)
 	ctx, err := relay_client.Login()
 	if err != nil {
 		t.Errorf("client login failed: %v", err)
@@ -63,6 +1052,7 @@ func testClient(name string, life time.Duration, t *testing.T) {
 		}
 	}()
 
+	danmaku_count := 0
 	for {
 		arr, err := relay_client.ReadMessages(ctx)
 		if err == io.EOF {
@@ -78,11 +1068,70 @@ func testClient(name string, life time.Duration, t *testing.T) {
 		for _, item := range arr {
 			data := item.Data
 			if item.Cmd == dm.CMD_DANMU_MSG {
-				data = relay_client.ReadBodyBytes(data, "info")
+				danmaku, err := events.DecodeDanmaku(item.Data)
+				if err != nil {
+					t.Errorf("[%s] decode danmaku failed: %v", name, err)
+					continue
+				}
+				data = []byte(danmaku.Sender.UserName + ": " + danmaku.Content)
+				danmaku_count++
+			}
+			if item.Cmd == dm.CMD_SEND_GIFT {
+				t.Errorf("[%s] recieved unsubscribed cmd %s", name, item.Cmd)
 			}
 			logger().Printf("[%s]  room=%d cmd=%s msg_type=%d data=%s", name, item.RoomID, item.Cmd, item.MsgType, string(data))
 		}
 	}
+
+	if danmaku_count == 0 {
+		t.Errorf("[%s] no danmaku recieved", name)
+	}
+}
+
+func testRoomLifecycle(t *testing.T) {
+	room := test_upstream.Room(1000)
+	sess := newTestSession("lifecycle", t)
+	defer sess.close()
+
+	if err := sess.client.Subscribe([]client.MsgSubscribeRoom{{RoomID: 1000, Cmds: []string{"*"}}}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	if !sess.waitFor(time.Second, msgTypeOf(1000, client.MSG_TYPE_WS_CONNECT)) {
+		t.Fatalf("room not connected")
+	}
+
+	// upstream reconnects after disconnect
+	room.FailDials(1)
+	room.Disconnect()
+	if !sess.waitFor(time.Second, msgTypeOf(1000, client.MSG_TYPE_WS_DISCONNECT)) {
+		t.Fatalf("room disconnect not notified")
+	}
+	if !sess.waitFor(3*time.Second, msgTypeOf(1000, client.MSG_TYPE_WS_CONNECT)) {
+		t.Fatalf("room not reconnected")
+	}
+	if room.Dials() != 3 {
+		t.Errorf("expect 3 dials, got %d", room.Dials())
+	}
+
+	// replacing subscriptions keeps the room connected
+	for i := 0; i < 3; i++ {
+		if err := sess.client.Subscribe([]client.MsgSubscribeRoom{{RoomID: 1000, Cmds: []string{"*"}}}); err != nil {
+			t.Fatalf("subscribe failed: %v", err)
+		}
+	}
+	time.Sleep(gConf.RoomIdleTimeout + 100*time.Millisecond)
+	if room.Conns() != 1 {
+		t.Errorf("room connection released while subscribed")
+	}
+
+	// upstream connection released after idle timeout
+	if err := sess.client.Subscribe(nil); err != nil {
+		t.Fatalf("unsubscribe failed: %v", err)
+	}
+	time.Sleep(gConf.RoomIdleTimeout + 300*time.Millisecond)
+	if room.Conns() != 0 {
+		t.Errorf("room connection not released after idle timeout")
+	}
 }
 
 func TestAll(t *testing.T) {
@@ -90,15 +1139,41 @@ func TestAll(t *testing.T) {
 		return
 	}
 
-	// server shutdown after 10 seconds
+	stop := make(chan struct{})
+	defer close(stop)
+	go test_upstream.Room(7777).Play([]dmmock.Event{
+		dmmock.DanmakuEvent(50*time.Millisecond, 1, "user1", "hello"),
+		dmmock.GiftEvent(50*time.Millisecond, 2, "user2", "flower", 1, 100),
+	}, true, stop)
+
+	defer os.RemoveAll(test_record_dir)
+
+	t.Run("RoomLifecycle", testRoomLifecycle)
+	t.Run("Replay", testReplay)
+	t.Run("WebSocket", testWebSocket)
+	t.Run("SSE", testSSE)
+	t.Run("Webhook", testWebhook)
+	t.Run("RedisSink", testRedisSink)
+	t.Run("Metrics", testMetrics)
+	t.Run("Admin", testAdmin)
+	t.Run("Session", testManagedSession)
+	t.Run("SeqAck", testSeqAck)
+	t.Run("Resume", testResume)
+	t.Run("SubscribeRemove", testSubscribeRemove)
+	t.Run("ListSubscriptions", testListSubscriptions)
+	t.Run("ClientContext", testClientContext)
+	t.Run("Handlers", testClientHandlers)
+	t.Run("Failover", testFailover)
+
+	// server shutdown after 2 seconds
 	go func() {
-		time.Sleep(time.Second * 10)
+		time.Sleep(time.Second * 2)
 		gServer.Close()
 	}()
 
-	go testClient("test1", 5*time.Second, t)
-	go testClient("test2", 15*time.Second, t)
-	time.Sleep(5 * time.Second)
-	go testClient("test3", 5*time.Second, t)
+	go testClient("test1", time.Second, t)
+	go testClient("test2", 3*time.Second, t)
+	time.Sleep(time.Second)
+	go testClient("test3", 500*time.Millisecond, t)
 	wgAll.Wait()
 }
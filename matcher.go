@@ -0,0 +1,79 @@
+package main
+
+import "strings"
+
+// cmdMatcher matches cmds against subscribed patterns, which could be:
+//
+//	"*"          all cmds
+//	"DANMU_MSG*" cmds with prefix DANMU_MSG, e.g. DANMU_MSG:4:0:2:2:2:0
+//	"DANMU_MSG"  exactly DANMU_MSG
+//	"!PATTERN"   exclude cmds matching PATTERN
+//
+// A cmd is matched if it matches any inclusion pattern and none of exclusion patterns,
+// all cmds not excluded are matched if there are only exclusion patterns.
+type cmdMatcher struct {
+	include cmdPatterns
+	exclude cmdPatterns
+}
+
+type cmdPatterns struct {
+	all      bool
+	exact    map[string]struct{}
+	prefixes []string
+}
+
+func compileCmdMatcher(cmds []string) *cmdMatcher {
+	ret := &cmdMatcher{}
+	for _, cmd := range cmds {
+		if strings.HasPrefix(cmd, "!") {
+			ret.exclude.add(cmd[1:])
+		} else {
+			ret.include.add(cmd)
+		}
+	}
+	if ret.include.empty() && !ret.exclude.empty() {
+		ret.include.all = true
+	}
+	return ret
+}
+
+func (m *cmdMatcher) Match(cmd string) bool {
+	return m.include.match(cmd) && !m.exclude.match(cmd)
+}
+
+func (p *cmdPatterns) add(pattern string) {
+	if len(pattern) == 0 {
+		return
+	}
+	if pattern == "*" {
+		p.all = true
+		return
+	}
+	if strings.HasSuffix(pattern, "*") {
+		p.prefixes = append(p.prefixes, pattern[:len(pattern)-1])
+		return
+	}
+	if p.exact == nil {
+		p.exact = make(map[string]struct{})
+	}
+	p.exact[pattern] = struct{}{}
+}
+
+func (p *cmdPatterns) empty() bool {
+	return !p.all && len(p.exact) == 0 && len(p.prefixes) == 0
+}
+
+func (p *cmdPatterns) match(cmd string) bool {
+	if p.all {
+		return true
+	}
+	if _, ok := p.exact[cmd]; ok {
+		return true
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(cmd, prefix) {
+			return true
+		}
+	}
+	return false
+}
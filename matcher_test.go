@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestCmdMatcher(t *testing.T) {
+	cases := []struct {
+		patterns []string
+		cmd      string
+		match    bool
+	}{
+		{nil, "DANMU_MSG", false},
+		{[]string{"DANMU_MSG"}, "DANMU_MSG", true},
+		{[]string{"DANMU_MSG"}, "DANMU_MSG:4:0:2:2:2:0", false},
+		{[]string{"DANMU_MSG*"}, "DANMU_MSG:4:0:2:2:2:0", true},
+		{[]string{"DANMU_MSG*"}, "SEND_GIFT", false},
+		{[]string{"*"}, "SEND_GIFT", true},
+		{[]string{"*", "!INTERACT_WORD"}, "INTERACT_WORD", false},
+		{[]string{"*", "!INTERACT_WORD"}, "SEND_GIFT", true},
+		{[]string{"!SUPER_CHAT_MESSAGE*"}, "SUPER_CHAT_MESSAGE_JPN", false},
+		{[]string{"!SUPER_CHAT_MESSAGE*"}, "GUARD_BUY", true},
+		{[]string{"DANMU_MSG*", "!DANMU_MSG:4*"}, "DANMU_MSG:4:0:2:2:2:0", false},
+		{[]string{"DANMU_MSG*", "!DANMU_MSG:4*"}, "DANMU_MSG", true},
+	}
+
+	for _, item := range cases {
+		if ret := compileCmdMatcher(item.patterns).Match(item.cmd); ret != item.match {
+			t.Errorf("patterns %v match %s: expect %v, got %v", item.patterns, item.cmd, item.match, ret)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// metrics are exposed in prometheus text format on gConf.Metrics.Addr
+
+const defaultMetricsPath = "/metrics"
+
+// metricCounters are incremented atomically from anywhere
+type metricCounters struct {
+	upstream_dials         uint64
+	upstream_dial_failures uint64
+	upstream_disconnects   uint64
+
+	batches_sent         uint64
+	dropped_msgs         uint64 // dropped by overflow policies
+	dropped_batches      uint64 // unacked batches dropped for exceeding maxUnackedBatches or cache byte limit
+	overflow_disconnects uint64
+}
+
+var gCounters metricCounters
+
+func countMetric(counter *uint64, n uint64) {
+	atomic.AddUint64(counter, n)
+}
+
+// dmMetrics is a snapshot of dmManager taken in a job
+type dmMetrics struct {
+	job_queue   int
+	rooms       int // rooms with subscribers
+	room_msgs   map[int]map[string]uint64
+	subscribers []subscriberMetrics
+}
+
+type subscriberMetrics struct {
+	id          uint32
+	detached    bool
+	cache_msgs  int
+	cache_bytes int
+	mailbox     int    // batches in mailbox not yet sent to client
+	unacked     int    // batches retained for redelivery
+	lag         uint64 // batches sent but not acked, ack mode only
+}
+
+func initMetrics() bool {
+	if len(gConf.Metrics.Addr) == 0 {
+		return true
+	}
+	lis, err := net.Listen("tcp", gConf.Metrics.Addr)
+	if err != nil {
+		logger().Printf("listen on metrics address %s failed: %v", gConf.Metrics.Addr, err)
+		return false
+	}
+	path := gConf.Metrics.Path
+	if len(path) == 0 {
+		path = defaultMetricsPath
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, metricsHandler)
+	serveHTTP("metrics", lis, mux)
+	return true
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	dm_metrics, err := gDanmaku.Metrics()
+	if err != nil {
+		http.Error(w, "server shutdown", http.StatusServiceUnavailable)
+		return
+	}
+	upstream := gClientMgr.StateCounts()
+
+	out := &metricsWriter{}
+	out.family("brelay_upstream_dials_total", "counter", "Dials to live rooms, including reconnects.")
+	out.sample("brelay_upstream_dials_total", nil, atomic.LoadUint64(&gCounters.upstream_dials))
+	out.family("brelay_upstream_dial_failures_total", "counter", "Failed dials to live rooms.")
+	out.sample("brelay_upstream_dial_failures_total", nil, atomic.LoadUint64(&gCounters.upstream_dial_failures))
+	out.family("brelay_upstream_disconnects_total", "counter", "Unexpected disconnections from live rooms, each followed by reconnecting.")
+	out.sample("brelay_upstream_disconnects_total", nil, atomic.LoadUint64(&gCounters.upstream_disconnects))
+	out.family("brelay_upstream_rooms", "gauge", "Live rooms with upstream connections by state.")
+	for _, state := range []int{DM_CLIENT_STATE_CONNECTING, DM_CLIENT_STATE_CONNECTED} {
+		out.sample("brelay_upstream_rooms", []string{"state", upstreamStateName(state)}, uint64(upstream[state]))
+	}
+
+	out.family("brelay_rooms_subscribed", "gauge", "Live rooms with subscribers.")
+	out.sample("brelay_rooms_subscribed", nil, uint64(dm_metrics.rooms))
+	out.family("brelay_job_queue_depth", "gauge", "Pending jobs of danmaku manager.")
+	out.sample("brelay_job_queue_depth", nil, uint64(dm_metrics.job_queue))
+
+	out.family("brelay_room_msgs_total", "counter", "Msgs received from live rooms by cmd.")
+	room_ids := make([]int, 0, len(dm_metrics.room_msgs))
+	for room_id := range dm_metrics.room_msgs {
+		room_ids = append(room_ids, room_id)
+	}
+	sort.Ints(room_ids)
+	for _, room_id := range room_ids {
+		cmds := make([]string, 0, len(dm_metrics.room_msgs[room_id]))
+		for cmd := range dm_metrics.room_msgs[room_id] {
+			cmds = append(cmds, cmd)
+		}
+		sort.Strings(cmds)
+		for _, cmd := range cmds {
+			out.sample("brelay_room_msgs_total", []string{"room", strconv.Itoa(room_id), "cmd", cmd}, dm_metrics.room_msgs[room_id][cmd])
+		}
+	}
+
+	out.family("brelay_batches_sent_total", "counter", "Batches sent to subscribers.")
+	out.sample("brelay_batches_sent_total", nil, atomic.LoadUint64(&gCounters.batches_sent))
+	out.family("brelay_dropped_msgs_total", "counter", "Msgs dropped by cache overflow policies.")
+	out.sample("brelay_dropped_msgs_total", nil, atomic.LoadUint64(&gCounters.dropped_msgs))
+	out.family("brelay_dropped_batches_total", "counter", "Unacked batches dropped before redelivery.")
+	out.sample("brelay_dropped_batches_total", nil, atomic.LoadUint64(&gCounters.dropped_batches))
+	out.family("brelay_overflow_disconnects_total", "counter", "Subscribers logged out by disconnect overflow policy.")
+	out.sample("brelay_overflow_disconnects_total", nil, atomic.LoadUint64(&gCounters.overflow_disconnects))
+
+	detached := 0
+	for _, sub := range dm_metrics.subscribers {
+		if sub.detached {
+			detached++
+		}
+	}
+	out.family("brelay_subscribers", "gauge", "Subscribers by state.")
+	out.sample("brelay_subscribers", []string{"state", "attached"}, uint64(len(dm_metrics.subscribers)-detached))
+	out.sample("brelay_subscribers", []string{"state", "detached"}, uint64(detached))
+
+	per_sub := []struct {
+		name, help string
+		value      func(sub *subscriberMetrics) uint64
+	}{
+		{"brelay_subscriber_cache_msgs", "Msgs cached for a subscriber.", func(sub *subscriberMetrics) uint64 { return uint64(sub.cache_msgs) }},
+		{"brelay_subscriber_cache_bytes", "Bytes of msgs cached for a subscriber.", func(sub *subscriberMetrics) uint64 { return uint64(sub.cache_bytes) }},
+		{"brelay_subscriber_mailbox_batches", "Batches flushed but not yet written to a subscriber.", func(sub *subscriberMetrics) uint64 { return uint64(sub.mailbox) }},
+		{"brelay_subscriber_unacked_batches", "Batches retained for redelivery to a subscriber.", func(sub *subscriberMetrics) uint64 { return uint64(sub.unacked) }},
+		{"brelay_subscriber_lag_batches", "Batches sent but not acked by a subscriber in ack mode.", func(sub *subscriberMetrics) uint64 { return sub.lag }},
+	}
+	for _, item := range per_sub {
+		out.family(item.name, "gauge", item.help)
+		for idx := range dm_metrics.subscribers {
+			sub := &dm_metrics.subscribers[idx]
+			out.sample(item.name, []string{"subscriber", strconv.FormatUint(uint64(sub.id), 10)}, item.value(sub))
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(out.buf.Bytes())
+}
+
+func upstreamStateName(state int) string {
+	switch state {
+	case DM_CLIENT_STATE_CONNECTING:
+		return "connecting"
+	case DM_CLIENT_STATE_CONNECTED:
+		return "connected"
+	}
+	return "init"
+}
+
+type metricsWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *metricsWriter) family(name, metric_type, help string) {
+	w.buf.WriteString("# HELP " + name + " " + help + "\n")
+	w.buf.WriteString("# TYPE " + name + " " + metric_type + "\n")
+}
+
+// sample writes a sample, labels are name value pairs
+func (w *metricsWriter) sample(name string, labels []string, value uint64) {
+	w.buf.WriteString(name)
+	if len(labels) > 0 {
+		w.buf.WriteByte('{')
+		for idx := 0; idx+1 < len(labels); idx += 2 {
+			if idx > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.buf.WriteString(labels[idx] + `="` + metricsLabelEscaper.Replace(labels[idx+1]) + `"`)
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteString(" " + strconv.FormatUint(value, 10) + "\n")
+}
+
+var metricsLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// projection strips raw json of room msgs down to a list of dot separated json paths, the
+// structure of kept values is preserved. Elements of arrays before a kept index are replaced
+// with null so that indexes remain unchanged, e.g. projecting "info.1" of DANMU_MSG results
+// in {"cmd":"DANMU_MSG","info":[null,"danmaku text"]}. The "cmd" field is always kept.
+type projection struct {
+	key  string // normalized fields, identical projections share the same key
+	root *projNode
+}
+
+type projNode struct {
+	keep     bool // keep the whole value
+	children map[string]*projNode
+	max_idx  int // max child index when applied to an array
+}
+
+func compileProjection(fields []string) *projection {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	sorted := append([]string{}, fields...)
+	sort.Strings(sorted)
+	ret := &projection{key: strings.Join(sorted, ","), root: &projNode{}}
+	ret.root.add([]string{"cmd"})
+	for _, field := range sorted {
+		if len(field) > 0 {
+			ret.root.add(strings.Split(field, "."))
+		}
+	}
+	return ret
+}
+
+func (n *projNode) add(path []string) {
+	if n.keep {
+		return
+	}
+	if len(path) == 0 {
+		n.keep = true
+		n.children = nil
+		return
+	}
+	if n.children == nil {
+		n.children = make(map[string]*projNode)
+		n.max_idx = -1
+	}
+	if idx, err := strconv.Atoi(path[0]); err == nil && idx > n.max_idx {
+		n.max_idx = idx
+	}
+	child, ok := n.children[path[0]]
+	if !ok {
+		child = &projNode{}
+		n.children[path[0]] = child
+	}
+	child.add(path[1:])
+}
+
+func (p *projection) apply(data []byte) []byte {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	iter := json.BorrowIterator(data)
+	defer json.ReturnIterator(iter)
+	stream := json.BorrowStream(nil)
+	defer json.ReturnStream(stream)
+
+	if iter.WhatIsNext() != jsoniter.ObjectValue {
+		return data
+	}
+	p.root.write(iter, stream)
+	if iter.Error != nil || stream.Error != nil {
+		return data
+	}
+	return append([]byte{}, stream.Buffer()...)
+}
+
+// write projects the value iter points to, value type must be checked by caller
+func (n *projNode) write(iter *jsoniter.Iterator, stream *jsoniter.Stream) {
+	if n.keep {
+		stream.WriteRaw(string(iter.SkipAndReturnBytes()))
+		return
+	}
+
+	if iter.WhatIsNext() == jsoniter.ArrayValue {
+		stream.WriteArrayStart()
+		idx := 0
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			if idx > n.max_idx {
+				iter.Skip()
+				return true
+			}
+			if idx > 0 {
+				stream.WriteMore()
+			}
+			child, ok := n.children[strconv.Itoa(idx)]
+			if ok && child.accepts(iter.WhatIsNext()) {
+				child.write(iter, stream)
+			} else {
+				iter.Skip()
+				stream.WriteNil()
+			}
+			idx++
+			return true
+		})
+		stream.WriteArrayEnd()
+		return
+	}
+
+	stream.WriteObjectStart()
+	first := true
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+		child, ok := n.children[key]
+		if !ok || !child.accepts(iter.WhatIsNext()) {
+			iter.Skip()
+			return true
+		}
+		if !first {
+			stream.WriteMore()
+		}
+		first = false
+		stream.WriteObjectField(key)
+		child.write(iter, stream)
+		return true
+	})
+	stream.WriteObjectEnd()
+}
+
+func (n *projNode) accepts(value_type jsoniter.ValueType) bool {
+	return n.keep || value_type == jsoniter.ObjectValue || value_type == jsoniter.ArrayValue
+}
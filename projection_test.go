@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestProjection(t *testing.T) {
+	danmaku := []byte(`{"cmd":"DANMU_MSG","info":[[0,1,25],"hello",[12345,"uname",0],[]],"dm_v2":""}`)
+	gift := []byte(`{"cmd":"SEND_GIFT","data":{"giftName":"flower","price":1000,"uid":12345,"medal_info":{"level":3}}}`)
+
+	cases := []struct {
+		fields []string
+		data   []byte
+		expect string
+	}{
+		{[]string{"info.1", "info.2.0"}, danmaku, `{"cmd":"DANMU_MSG","info":[null,"hello",[12345]]}`},
+		{[]string{"info.1", "data.giftName"}, danmaku, `{"cmd":"DANMU_MSG","info":[null,"hello"]}`},
+		{[]string{"info.1", "data.giftName", "data.medal_info"}, gift, `{"cmd":"SEND_GIFT","data":{"giftName":"flower","medal_info":{"level":3}}}`},
+		{[]string{"data.giftName.x", "data"}, gift, string(gift)},
+		{[]string{"info.1.text"}, danmaku, `{"cmd":"DANMU_MSG","info":[null,null]}`},
+	}
+
+	for idx, item := range cases {
+		if ret := string(compileProjection(item.fields).apply(item.data)); ret != item.expect {
+			t.Errorf("case %d: expect %s, got %s", idx, item.expect, ret)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// recorder writes raw msgs of rooms to per-room, per-day append-only files. Each line of a
+// file is a json object like {"ts":1638760000000,"cmd":"DANMU_MSG","data":{...}}, where ts
+// is unix timestamp in milliseconds. Files are named as DIR/ROOM_ID/YYYYMMDD[.N].jsonl[.gz],
+// N increases when a file of the same day exceeds max file size.
+type recorder struct {
+	conf  RecorderConfig
+	rooms map[int]struct{}
+	ch    chan recordItem
+	files map[int]*recordFile // room_id => current file, accessed by run only
+}
+
+type recordItem struct {
+	ts      time.Time
+	room_id int
+	cmd     string
+	data    []byte
+}
+
+// recordEntry is a line in record files
+type recordEntry struct {
+	Ts   int64               `json:"ts"`
+	Cmd  string              `json:"cmd"`
+	Data jsoniter.RawMessage `json:"data"`
+}
+
+type recordFile struct {
+	day   string
+	index int
+	file  *os.File
+	size  int64 // bytes written to file
+	gz    *gzip.Writer
+	buf   *bufio.Writer
+}
+
+const recorderQueueSize = 4096
+const recorderFlushInterval = time.Second
+
+var gRecorder *recorder
+
+func initRecorder() bool {
+	conf := gConf.Recorder
+	if len(conf.Dir) == 0 {
+		return true
+	}
+	if err := os.MkdirAll(conf.Dir, 0755); err != nil {
+		logger().Printf("create recorder dir %s failed: %v", conf.Dir, err)
+		return false
+	}
+
+	gRecorder = &recorder{
+		conf:  conf,
+		rooms: make(map[int]struct{}),
+		ch:    make(chan recordItem, recorderQueueSize),
+		files: make(map[int]*recordFile),
+	}
+	for _, room_id := range conf.Rooms {
+		gRecorder.rooms[room_id] = struct{}{}
+	}
+
+	wgAll.Add(1)
+	go gRecorder.run()
+
+	// recorded rooms are connected even without subscribers
+	for _, room_id := range conf.Rooms {
+		gClientMgr.PinClient(room_id)
+	}
+	return true
+}
+
+// Record queues a raw msg of a room to be written, msgs are dropped if the queue is full
+func (r *recorder) Record(room_id int, cmd string, data []byte) {
+	if r == nil || isReplayRoom(room_id) {
+		return
+	}
+	if _, ok := r.rooms[room_id]; !ok && !r.conf.All {
+		return
+	}
+	select {
+	case r.ch <- recordItem{ts: time.Now(), room_id: room_id, cmd: cmd, data: data}:
+	default:
+		logger().Printf("recorder queue full, msg of room %d dropped", room_id)
+	}
+}
+
+func (r *recorder) run() {
+	defer wgAll.Done()
+	ticker := time.NewTicker(recorderFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case item := <-r.ch:
+			r.write(&item)
+		case <-ticker.C:
+			r.flush()
+		case <-gServer.CloseChannel():
+			for {
+				select {
+				case item := <-r.ch:
+					r.write(&item)
+				default:
+					r.closeAll()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *recorder) write(item *recordItem) {
+	line, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(&recordEntry{
+		Ts:   item.ts.UnixNano() / int64(time.Millisecond),
+		Cmd:  item.cmd,
+		Data: item.data,
+	})
+	if err != nil {
+		logger().Printf("encode record of room %d failed: %v", item.room_id, err)
+		return
+	}
+	line = append(line, '\n')
+
+	file, err := r.fileFor(item.room_id, item.ts)
+	if err != nil {
+		logger().Printf("open record file of room %d failed: %v", item.room_id, err)
+		return
+	}
+	if _, err = file.buf.Write(line); err != nil {
+		logger().Printf("write record file of room %d failed: %v", item.room_id, err)
+		r.closeFile(item.room_id)
+	}
+}
+
+// fileFor returns current file of a room, rotating it on day change or size limit
+func (r *recorder) fileFor(room_id int, ts time.Time) (*recordFile, error) {
+	day := ts.Format("20060102")
+	file, ok := r.files[room_id]
+	if ok && file.day == day && (r.conf.MaxFileSize <= 0 || file.size < r.conf.MaxFileSize) {
+		return file, nil
+	}
+
+	index := 0
+	if ok {
+		r.closeFile(room_id)
+		if file.day == day {
+			index = file.index + 1
+		}
+	}
+
+	dir := filepath.Join(r.conf.Dir, strconv.Itoa(room_id))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// skip full files of the same day written before
+	name := ""
+	for {
+		name = filepath.Join(dir, recordFileName(day, index, r.conf.Compress))
+		stat, err := os.Stat(name)
+		if err != nil || r.conf.MaxFileSize <= 0 || stat.Size() < r.conf.MaxFileSize {
+			break
+		}
+		index++
+	}
+
+	tmp, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return nil, err
+	}
+
+	file = &recordFile{day: day, index: index, file: tmp, size: stat.Size()}
+	var out io.Writer = &countingWriter{w: tmp, n: &file.size}
+	if r.conf.Compress {
+		file.gz = gzip.NewWriter(out) // appended as a new gzip member
+		out = file.gz
+	}
+	file.buf = bufio.NewWriter(out)
+	r.files[room_id] = file
+	return file, nil
+}
+
+func recordFileName(day string, index int, compress bool) string {
+	name := day
+	if index > 0 {
+		name += fmt.Sprintf(".%d", index)
+	}
+	name += ".jsonl"
+	if compress {
+		name += ".gz"
+	}
+	return name
+}
+
+func (r *recorder) flush() {
+	for room_id, file := range r.files {
+		err := file.buf.Flush()
+		if err == nil && file.gz != nil {
+			err = file.gz.Flush()
+		}
+		if err != nil {
+			logger().Printf("flush record file of room %d failed: %v", room_id, err)
+			r.closeFile(room_id)
+		}
+	}
+}
+
+func (r *recorder) closeFile(room_id int) {
+	file, ok := r.files[room_id]
+	if !ok {
+		return
+	}
+	delete(r.files, room_id)
+	file.buf.Flush()
+	if file.gz != nil {
+		file.gz.Close()
+	}
+	file.file.Close()
+}
+
+func (r *recorder) closeAll() {
+	for room_id := range r.files {
+		r.closeFile(room_id)
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (w *countingWriter) Write(buf []byte) (int, error) {
+	n, err := w.w.Write(buf)
+	*w.n += int64(n)
+	return n, err
+}
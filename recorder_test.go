@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRecorderRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "brelay_recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := &recorder{
+		conf:  RecorderConfig{Dir: dir, Compress: true, MaxFileSize: 1},
+		files: make(map[int]*recordFile),
+	}
+	ts := time.Date(2021, 12, 6, 10, 0, 0, 0, time.Local)
+	r.write(&recordItem{ts: ts, room_id: 1, cmd: "DANMU_MSG", data: []byte(`{"cmd":"DANMU_MSG"}`)})
+	r.flush()
+	r.write(&recordItem{ts: ts, room_id: 1, cmd: "SEND_GIFT", data: []byte(`{"cmd":"SEND_GIFT"}`)})
+	r.write(&recordItem{ts: ts.Add(24 * time.Hour), room_id: 1, cmd: "LIVE", data: []byte(`{"cmd":"LIVE"}`)})
+	r.closeAll()
+
+	expect := map[string]string{
+		"20211206.jsonl.gz":   `{"ts":` + itoa64(ts) + `,"cmd":"DANMU_MSG","data":{"cmd":"DANMU_MSG"}}`,
+		"20211206.1.jsonl.gz": `{"ts":` + itoa64(ts) + `,"cmd":"SEND_GIFT","data":{"cmd":"SEND_GIFT"}}`,
+		"20211207.jsonl.gz":   `{"ts":` + itoa64(ts.Add(24*time.Hour)) + `,"cmd":"LIVE","data":{"cmd":"LIVE"}}`,
+	}
+	for name, line := range expect {
+		file, err := os.Open(filepath.Join(dir, "1", name))
+		if err != nil {
+			t.Fatalf("open %s failed: %v", name, err)
+		}
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("read %s failed: %v", name, err)
+		}
+		scanner := bufio.NewScanner(gz)
+		if !scanner.Scan() || scanner.Text() != line {
+			t.Errorf("%s: got %q, expect %q", name, scanner.Text(), line)
+		}
+		file.Close()
+	}
+}
+
+func itoa64(ts time.Time) string {
+	return strconv.FormatInt(ts.UnixNano()/int64(time.Millisecond), 10)
+}
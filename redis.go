@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/zerozwt/brelay/client"
+)
+
+// Redis sinks PUBLISH every msg of a batch to channel PREFIX:ROOM_ID:CMD with raw json data
+// as payload, e.g. brelay:7777:DANMU_MSG. State msgs are published to channels named by
+// msgTypeNames, e.g. brelay:7777:ws_connect. Commands of a batch are pipelined, the whole
+// batch is published again after reconnecting if it failed, so msgs may be duplicated.
+
+const defaultRedisTimeout = 5 * time.Second
+const defaultRedisPrefix = "brelay"
+
+type redisSink struct {
+	conf RedisSinkConfig
+	conn *respConn // nil while disconnected, accessed by sink goroutine only
+}
+
+func initRedisSinks() bool {
+	for idx := range gConf.RedisSinks {
+		conf := gConf.RedisSinks[idx]
+		if len(conf.Addr) == 0 {
+			logger().Printf("addr of redis sink %s not specified", conf.Name)
+			return false
+		}
+		if len(conf.Prefix) == 0 {
+			conf.Prefix = defaultRedisPrefix
+		}
+		if conf.Timeout <= 0 {
+			conf.Timeout = defaultRedisTimeout
+		}
+		conf.setDefaults()
+
+		sink := &redisSink{conf: conf}
+		if !startSink("redis "+conf.Name, conf.Rooms, conf.FlushInterval, sink.deliver) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *redisSink) deliver(batch *client.MsgSubscribeBatch) {
+	s.conf.SinkRetryConfig.deliver(s.conf.Name, batch, func() (bool, error) {
+		err := s.publish(batch)
+		if err != nil {
+			if _, ok := err.(respError); ok {
+				return false, err // rejected by server, e.g. NOAUTH
+			}
+			if s.conn != nil {
+				s.conn.Close()
+				s.conn = nil
+			}
+		}
+		return true, err
+	})
+}
+
+func (s *redisSink) publish(batch *client.MsgSubscribeBatch) error {
+	if s.conn == nil {
+		conn, err := dialRESP(s.conf.Addr, s.conf.Username, s.conf.Password, s.conf.Timeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	s.conn.SetDeadline(time.Now().Add(s.conf.Timeout))
+	for _, msg := range batch.Msgs {
+		s.conn.WriteCommand("PUBLISH", s.channel(&msg), string(msg.Data))
+	}
+	if err := s.conn.Flush(); err != nil {
+		return err
+	}
+
+	var reply_err error
+	for range batch.Msgs {
+		if _, err := s.conn.ReadReply(); err != nil {
+			if _, ok := err.(respError); !ok {
+				return err
+			}
+			reply_err = err
+		}
+	}
+	return reply_err
+}
+
+func (s *redisSink) channel(msg *client.MsgSubscribeData) string {
+	name := msg.Cmd
+	if msg.MsgType != client.MSG_TYPE_DATA {
+		name = msgTypeNames[msg.MsgType]
+	}
+	return s.conf.Prefix + ":" + strconv.Itoa(msg.RoomID) + ":" + name
+}
+
+// respConn is a minimal client of redis serialization protocol
+type respConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// respError is an error reply from server
+type respError string
+
+func (e respError) Error() string {
+	return string(e)
+}
+
+func dialRESP(addr, username, password string, timeout time.Duration) (*respConn, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	ret := &respConn{Conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+	if len(password) == 0 {
+		return ret, nil
+	}
+
+	ret.SetDeadline(time.Now().Add(timeout))
+	if len(username) > 0 {
+		ret.WriteCommand("AUTH", username, password)
+	} else {
+		ret.WriteCommand("AUTH", password)
+	}
+	if err = ret.Flush(); err == nil {
+		_, err = ret.ReadReply()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (c *respConn) WriteCommand(args ...string) {
+	c.w.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		c.w.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n")
+		c.w.WriteString(arg)
+		c.w.WriteString("\r\n")
+	}
+}
+
+func (c *respConn) Flush() error {
+	return c.w.Flush()
+}
+
+// ReadReply reads a reply, which is a string, an int64, nil or an []interface{} of them. Error
+// replies are returned as respError.
+func (c *respConn) ReadReply() (interface{}, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("empty resp reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, respError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		ret := make([]interface{}, 0, size)
+		for idx := 0; idx < size; idx++ {
+			item, err := c.ReadReply()
+			if _, ok := err.(respError); err != nil && !ok {
+				return nil, err
+			}
+			ret = append(ret, item)
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("invalid resp reply %q", line)
+}
+
+func (c *respConn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("invalid resp line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
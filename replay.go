@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	dm "github.com/zerozwt/BLiveDanmaku"
+)
+
+// replayRoom is a virtual live room which replays record files written by recorder
+type replayRoom struct {
+	conf  ReplayConfig
+	files []string
+}
+
+type replayConn struct {
+	room    *replayRoom
+	handler *upstreamHandler
+	info    atomic.Value // *dm.RoomInfo
+	stop_ch chan struct{}
+	closed  int32
+}
+
+var gReplayRooms map[int]*replayRoom = make(map[int]*replayRoom)
+
+// min wait before replaying from the first file again
+const replayLoopInterval = time.Second
+
+func initReplays() bool {
+	for _, conf := range gConf.Replays {
+		if _, ok := gReplayRooms[conf.RoomID]; ok {
+			logger().Printf("duplicated replay room %d", conf.RoomID)
+			return false
+		}
+		room, err := newReplayRoom(conf)
+		if err != nil {
+			logger().Printf("init replay room %d failed: %v", conf.RoomID, err)
+			return false
+		}
+		gReplayRooms[conf.RoomID] = room
+	}
+
+	if len(gReplayRooms) > 0 {
+		gClientMgr.upstream = replayDialer(gClientMgr.upstream)
+	}
+	return true
+}
+
+func newReplayRoom(conf ReplayConfig) (*replayRoom, error) {
+	if conf.RoomID <= 0 {
+		return nil, errors.New("invalid virtual room id")
+	}
+	if conf.Speed < 0 {
+		return nil, fmt.Errorf("invalid speed %v", conf.Speed)
+	}
+	if conf.Speed == 0 {
+		conf.Speed = 1
+	}
+
+	files := []string{}
+	for _, pattern := range conf.Files {
+		tmp, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(tmp)
+		files = append(files, tmp...)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no record files found")
+	}
+	return &replayRoom{conf: conf, files: files}, nil
+}
+
+// replayDialer dials virtual replay rooms, other rooms are dialed by next
+func replayDialer(next upstreamDialer) upstreamDialer {
+	return func(room_id int, handler *upstreamHandler) (upstreamConn, error) {
+		room, ok := gReplayRooms[room_id]
+		if !ok {
+			return next(room_id, handler)
+		}
+		return room.dial(handler), nil
+	}
+}
+
+func isReplayRoom(room_id int) bool {
+	_, ok := gReplayRooms[room_id]
+	return ok
+}
+
+func (r *replayRoom) dial(handler *upstreamHandler) *replayConn {
+	info := &dm.RoomInfo{}
+	info.Base.RoomID = r.conf.RoomID
+	info.Base.Title = r.conf.Title
+	if len(info.Base.Title) == 0 {
+		info.Base.Title = fmt.Sprintf("replay room %d", r.conf.RoomID)
+	}
+
+	ret := &replayConn{room: r, handler: handler, stop_ch: make(chan struct{})}
+	ret.info.Store(info)
+	go ret.play()
+	return ret
+}
+
+func (c *replayConn) Room() *dm.RoomInfo {
+	ret := *(c.info.Load().(*dm.RoomInfo))
+	return &ret
+}
+
+func (c *replayConn) Close() {
+	if atomic.CompareAndSwapInt32(&(c.closed), 0, 1) {
+		close(c.stop_ch)
+	}
+}
+
+// play emits msgs of record files at recorded pace divided by speed, gaps longer than max
+// gap are shortened. The connection stays open but quiet after all files are played unless
+// loop is set, passes are separated by max gap or at least replayLoopInterval, and looping
+// stops if a whole pass emitted nothing.
+func (c *replayConn) play() {
+	for {
+		var last_ts int64 = -1
+		emitted := 0
+		for _, name := range c.room.files {
+			err := readRecordFile(name, func(entry *recordEntry) bool {
+				if last_ts >= 0 && entry.Ts > last_ts {
+					wait := time.Duration(float64(time.Duration(entry.Ts-last_ts)*time.Millisecond) / c.room.conf.Speed)
+					if c.room.conf.MaxGap > 0 && wait > c.room.conf.MaxGap {
+						wait = c.room.conf.MaxGap
+					}
+					select {
+					case <-time.After(wait):
+					case <-c.stop_ch:
+						return false
+					case <-gServer.CloseChannel():
+						return false
+					}
+				}
+				last_ts = entry.Ts
+				emitted++
+				return c.emit(entry)
+			})
+			if err != nil {
+				logger().Printf("replay room %d: read record file %s failed: %v", c.room.conf.RoomID, name, err)
+			}
+			if c.stopped() {
+				return
+			}
+		}
+		if !c.room.conf.Loop {
+			return
+		}
+		if emitted == 0 {
+			logger().Printf("replay room %d: nothing played, stop looping", c.room.conf.RoomID)
+			return
+		}
+
+		wait := c.room.conf.MaxGap
+		if wait < replayLoopInterval {
+			wait = replayLoopInterval
+		}
+		select {
+		case <-time.After(wait):
+		case <-c.stop_ch:
+			return
+		case <-gServer.CloseChannel():
+			return
+		}
+	}
+}
+
+func (c *replayConn) emit(entry *recordEntry) bool {
+	if c.stopped() {
+		return false
+	}
+	c.handler.OnMsg(c, entry.Data)
+	if entry.Cmd == dm.CMD_LIVE || entry.Cmd == dm.CMD_PREPARING {
+		info := c.Room()
+		info.Base.LiveStatus = 0
+		if entry.Cmd == dm.CMD_LIVE {
+			info.Base.LiveStatus = 1
+			info.Base.LiveStartTime = time.Now().Unix()
+		}
+		c.info.Store(info)
+		c.handler.OnLiveStateChange(c, entry.Cmd)
+	}
+	return true
+}
+
+func (c *replayConn) stopped() bool {
+	if atomic.LoadInt32(&(c.closed)) != 0 {
+		return true
+	}
+	select {
+	case <-gServer.CloseChannel():
+		return true
+	default:
+		return false
+	}
+}
+
+// readRecordFile calls cb on every entry of a record file until cb returns false, malformed
+// lines are skipped.
+func readRecordFile(name string, cb func(entry *recordEntry) bool) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var in io.Reader = file
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		in = gz
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			entry := recordEntry{}
+			if jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(line, &entry) == nil && len(entry.Data) > 0 {
+				if !cb(&entry) {
+					return nil
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package respmock is an in-process stand-in of redis server speaking RESP, it accepts AUTH,
+// PING and PUBLISH so that brelay's redis sinks could be tested without a redis server.
+package respmock
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Message is a published msg
+type Message struct {
+	Channel string
+	Payload string
+}
+
+type Server struct {
+	sync.Mutex
+	lis      net.Listener
+	password string
+	conns    map[net.Conn]struct{}
+	msgs     chan Message
+	closed   bool
+}
+
+// NewServer listens on addr, e.g. "localhost:0". AUTH is required before PUBLISH if password
+// is not empty. Published msgs are buffered up to buffer_size, later ones are dropped if
+// they are not received by Messages.
+func NewServer(addr, password string, buffer_size int) (*Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Server{
+		lis:      lis,
+		password: password,
+		conns:    make(map[net.Conn]struct{}),
+		msgs:     make(chan Message, buffer_size),
+	}
+	go ret.serve()
+	return ret, nil
+}
+
+// Addr returns address of the server
+func (s *Server) Addr() string {
+	return s.lis.Addr().String()
+}
+
+// Messages returns channel of published msgs
+func (s *Server) Messages() <-chan Message {
+	return s.msgs
+}
+
+// DropConns closes all client connections
+func (s *Server) DropConns() {
+	s.Lock()
+	defer s.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *Server) Close() {
+	s.Lock()
+	s.closed = true
+	s.Unlock()
+	s.lis.Close()
+	s.DropConns()
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.lis.Accept()
+		if err != nil {
+			return
+		}
+		s.Lock()
+		if s.closed {
+			s.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		s.Lock()
+		delete(s.conns, conn)
+		s.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	authed := len(s.password) == 0
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			if len(args) < 2 || args[len(args)-1] != s.password {
+				w.WriteString("-WRONGPASS invalid username-password pair\r\n")
+				break
+			}
+			authed = true
+			w.WriteString("+OK\r\n")
+		case "PING":
+			w.WriteString("+PONG\r\n")
+		case "PUBLISH":
+			if !authed {
+				w.WriteString("-NOAUTH Authentication required.\r\n")
+				break
+			}
+			if len(args) != 3 {
+				w.WriteString("-ERR wrong number of arguments for 'publish' command\r\n")
+				break
+			}
+			select {
+			case s.msgs <- Message{Channel: args[1], Payload: args[2]}:
+			default:
+			}
+			w.WriteString(":1\r\n")
+		default:
+			w.WriteString("-ERR unknown command '" + args[0] + "'\r\n")
+		}
+
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return strings.Fields(line), nil // inline command
+	}
+	count, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, 0, count)
+	for idx := 0; idx < count; idx++ {
+		line, err = readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, errors.New("respmock: bulk string expected")
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, errors.New("respmock: invalid bulk string length")
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		ret = append(ret, string(buf[:size]))
+	}
+	return ret, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/zerozwt/brelay/client"
+)
+
+// A sink is a server side subscriber which delivers its batches to an external service
+// instead of a client connection. Batches failed to be delivered are appended to a
+// dead-letter file.
+
+// msgTypeNames names state msgs for sinks and http inbound
+var msgTypeNames = map[byte]string{
+	client.MSG_TYPE_WS_CONNECT:     "ws_connect",
+	client.MSG_TYPE_WS_DISCONNECT:  "ws_disconnect",
+	client.MSG_TYPE_ROOM_CONN_FAIL: "room_conn_fail",
+	client.MSG_TYPE_DROPPED:        "dropped",
+}
+
+// startSink subscribes rooms with an internal subscriber and calls deliver with every batch
+// flushed to it until server shutdown.
+func startSink(name string, rooms []SinkRoomConfig, flush_interval time.Duration, deliver func(batch *client.MsgSubscribeBatch)) bool {
+	mb := newMailbox()
+	sub_id, err := gDanmaku.AllocSubscriberID(mb, &subscriberConf{name: "sink " + name, flush_interval: flush_interval})
+	if err != nil {
+		return false
+	}
+
+	for _, item := range rooms {
+		room := item.subscription()
+		info, err := newSubscriberInfo(sub_id, &room)
+		if err != nil {
+			logger().Printf("sink %s: invalid subscription of room %d: %v", name, item.RoomID, err)
+			gDanmaku.Logout(sub_id)
+			return false
+		}
+		gDanmaku.SubscribeRoom(item.RoomID, info)
+	}
+	logger().Printf("sink %s get an subscriber id %d", name, sub_id)
+
+	wgAll.Add(1)
+	go func() {
+		defer wgAll.Done()
+		for batch := range mb {
+			deliver(&batch)
+		}
+	}()
+	return true
+}
+
+func (conf *SinkRoomConfig) subscription() client.MsgSubscribeRoom {
+	ret := client.MsgSubscribeRoom{
+		RoomID:  conf.RoomID,
+		Cmds:    conf.Cmds,
+		Filters: conf.Filters,
+		Fields:  conf.Fields,
+	}
+	if len(ret.Cmds) == 0 {
+		ret.Cmds = []string{"*"}
+	}
+	return ret
+}
+
+const (
+	defaultSinkMaxRetries       = 5
+	defaultSinkRetryInterval    = time.Second
+	defaultSinkMaxRetryInterval = 30 * time.Second
+)
+
+var errSinkShutdown error = errors.New("server shutdown")
+
+func (conf *SinkRetryConfig) setDefaults() {
+	if conf.MaxRetries == 0 {
+		conf.MaxRetries = defaultSinkMaxRetries
+	}
+	if conf.RetryInterval <= 0 {
+		conf.RetryInterval = defaultSinkRetryInterval
+	}
+	if conf.MaxRetryInterval <= 0 {
+		conf.MaxRetryInterval = defaultSinkMaxRetryInterval
+	}
+}
+
+// deliver calls attempt until it succeeds or returns a non-retryable error, waiting with
+// exponential backoff between retries. The batch is written to dead-letter file if it could
+// not be delivered.
+func (conf *SinkRetryConfig) deliver(sink string, batch *client.MsgSubscribeBatch, attempt func() (retryable bool, err error)) {
+	wait := conf.RetryInterval
+	var err error
+	for retry := 0; ; retry++ {
+		var retryable bool
+		if retryable, err = attempt(); err == nil {
+			return
+		}
+		if !retryable || (conf.MaxRetries > 0 && retry >= conf.MaxRetries) {
+			break
+		}
+		logger().Printf("sink %s: deliver batch %d failed: %v, retry after %v", sink, batch.Seq, err, wait)
+		select {
+		case <-time.After(wait):
+		case <-gServer.CloseChannel():
+			writeDeadLetter(conf.DeadLetterFile, sink, batch, errSinkShutdown)
+			return
+		}
+		if wait *= 2; wait > conf.MaxRetryInterval {
+			wait = conf.MaxRetryInterval
+		}
+	}
+	writeDeadLetter(conf.DeadLetterFile, sink, batch, err)
+}
+
+// deadLetterEntry is a line of dead-letter files
+type deadLetterEntry struct {
+	Ts    int64      `json:"ts"` // unix timestamp in milliseconds
+	Sink  string     `json:"sink"`
+	Error string     `json:"error"`
+	Batch *jsonBatch `json:"batch"`
+}
+
+// writeDeadLetter appends a batch failed to be delivered to file, the batch is dropped if
+// file is empty.
+func writeDeadLetter(file, sink string, batch *client.MsgSubscribeBatch, reason error) {
+	logger().Printf("sink %s: deliver batch %d failed: %v", sink, batch.Seq, reason)
+	if len(file) == 0 {
+		return
+	}
+
+	line, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(&deadLetterEntry{
+		Ts:    time.Now().UnixNano() / int64(time.Millisecond),
+		Sink:  sink,
+		Error: reason.Error(),
+		Batch: newJSONBatch("", batch),
+	})
+	if err != nil {
+		logger().Printf("sink %s: encode dead letter failed: %v", sink, err)
+		return
+	}
+
+	out, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		logger().Printf("sink %s: open dead-letter file %s failed: %v", sink, file, err)
+		return
+	}
+	defer out.Close()
+	if _, err = out.Write(append(line, '\n')); err != nil {
+		logger().Printf("sink %s: write dead-letter file %s failed: %v", sink, file, err)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zerozwt/brelay/client"
+)
+
+// HTTP inbound streams msgs of a room as server-sent events:
+//
+//	GET /rooms/{id}/events?cmds=DANMU_MSG,SEND_GIFT&token=xxx
+//
+// token could also be given by header "Authorization: Bearer xxx", it should be one of tokens
+// of the inbound, or the login key if it is configured. Optional query params are fields
+// (comma separated projection paths), backfill, since and flush_ms, see
+// client.MsgSubscribeRoom. Each data msg is sent as an event named by its cmd with raw json
+// as data, state msgs are sent as events named ws_connect, ws_disconnect, room_conn_fail and
+// dropped.
+
+const sseHeartbeatInterval = 15 * time.Second
+
+func sseHandler(item *InboundConfig) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rooms/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		room_id, ok := parseSSEPath(r.URL.Path)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if !checkOrigin(item.AllowOrigins, r) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		if !checkToken(item.Tokens, r) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		serveSSE(w, r, room_id)
+	})
+	return mux
+}
+
+// parseSSEPath gets room id from path /rooms/{id}/events
+func parseSSEPath(path string) (int, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "rooms" || parts[2] != "events" {
+		return 0, false
+	}
+	room_id, err := strconv.Atoi(parts[1])
+	if err != nil || room_id <= 0 {
+		return 0, false
+	}
+	return room_id, true
+}
+
+func checkToken(tokens []string, r *http.Request) bool {
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); len(token) == 0 && strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if len(token) == 0 {
+		return false
+	}
+	// accept the configured login key but not the public default of loginKey, empty
+	// entries match nothing
+	for _, item := range append([]string{gConf.LoginKey}, tokens...) {
+		if len(item) > 0 && subtle.ConstantTimeCompare([]byte(item), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// sseSubscription builds subscription of room_id from query params
+func sseSubscription(r *http.Request, room_id int) (*client.MsgSubscribeRoom, uint32, error) {
+	query := r.URL.Query()
+	ret := &client.MsgSubscribeRoom{RoomID: room_id, Cmds: splitParam(query.Get("cmds")), Fields: splitParam(query.Get("fields"))}
+	if len(ret.Cmds) == 0 {
+		ret.Cmds = []string{"*"}
+	}
+
+	parse := func(name string) (int64, error) {
+		value := query.Get(name)
+		if len(value) == 0 {
+			return 0, nil
+		}
+		ret, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || ret < 0 {
+			return 0, fmt.Errorf("invalid %s: %s", name, value)
+		}
+		return ret, nil
+	}
+	backfill, err := parse("backfill")
+	if err != nil {
+		return nil, 0, err
+	}
+	since, err := parse("since")
+	if err != nil {
+		return nil, 0, err
+	}
+	flush_ms, err := parse("flush_ms")
+	if err != nil {
+		return nil, 0, err
+	}
+	ret.Backfill, ret.Since = uint32(backfill), since
+	return ret, uint32(flush_ms), nil
+}
+
+func splitParam(value string) []string {
+	ret := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+func serveSSE(w http.ResponseWriter, r *http.Request, room_id int) {
+	wgAll.Add(1)
+	defer wgAll.Done()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	room, flush_ms, err := sseSubscription(r, room_id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mb := newMailbox()
+	sub_id, err := gDanmaku.AllocSubscriberID(mb, &subscriberConf{
+		name:           "sse",
+		addr:           r.RemoteAddr,
+		flush_interval: time.Duration(flush_ms) * time.Millisecond,
+	})
+	if err != nil {
+		return // it has to be a ErrInterrupted
+	}
+	info, err := newSubscriberInfo(sub_id, room)
+	if err != nil {
+		gDanmaku.Logout(sub_id)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	gDanmaku.SubscribeRoom(room_id, info)
+	logger().Printf("sse client %s get an subscriber id %d for room %d", r.RemoteAddr, sub_id, room_id)
+	defer gDanmaku.Logout(sub_id)
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case batch, ok := <-mb:
+			if !ok {
+				return
+			}
+			if err := writeSSEBatch(w, &batch); err != nil {
+				logger().Printf("send batch msgs to sse client %s failed: %v", r.RemoteAddr, err)
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := w.Write([]byte(": ping\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			logger().Printf("sse client %s closed", r.RemoteAddr)
+			return
+		case <-gServer.CloseChannel():
+			return
+		}
+	}
+}
+
+func writeSSEBatch(w http.ResponseWriter, batch *client.MsgSubscribeBatch) error {
+	buf := make([]byte, 0, 1024)
+	for _, msg := range batch.Msgs {
+		name := msg.Cmd
+		if msg.MsgType != client.MSG_TYPE_DATA {
+			name = msgTypeNames[msg.MsgType]
+		}
+		data := msg.Data
+		if len(data) == 0 {
+			data = []byte("{}")
+		}
+		buf = append(buf, "event: "...)
+		buf = append(buf, name...)
+		buf = append(buf, "\ndata: "...)
+		buf = append(buf, bytes.Replace(data, []byte("\n"), []byte("\ndata: "), -1)...)
+		buf = append(buf, "\n\n"...)
+	}
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/zerozwt/brelay/client"
+)
+
+type subMailbox chan client.MsgSubscribeBatch
+
+const subMailboxSize = 8
+
+func newMailbox() subMailbox {
+	return make(subMailbox, subMailboxSize)
+}
+
+type subscriber struct {
+	id      uint32
+	name    string      // client name given on login
+	addr    string      // remote address of client, empty for server side subscribers
+	mailbox subMailbox  // recv channel, nil while detached
+	expire  *time.Timer // logout timer while detached
+
+	cache       []client.MsgSubscribeData // cached msgs
+	cache_bytes int
+	dropped     int    // msgs dropped by overflow policy since last flush
+	policy      string // overflow policy, one of client.OVERFLOW_*
+
+	flush_interval time.Duration
+	next_flush     time.Time
+
+	seq           uint64                     // seq of last batch sent to mailbox
+	ack           bool                       // retain sent batches until acked by client
+	unacked       []client.MsgSubscribeBatch // batches to be redelivered on resume, ordered by seq
+	unacked_bytes int                        // limited by cache byte limit
+}
+
+// subscriberConf holds options requested by client on login
+type subscriberConf struct {
+	name           string
+	addr           string
+	ack            bool
+	policy         string        // empty for gConf.CacheLimit.Policy
+	flush_interval time.Duration // 0 for gConf.FlushInterval
+}
+
+const maxUnackedBatches = 1024
+
+const (
+	defaultCacheMaxMsgs  = 10000
+	defaultCacheMaxBytes = 16 << 20
+
+	defaultFlushInterval = time.Second
+	minFlushInterval     = 10 * time.Millisecond
+
+	defaultMaxBatchMsgs  = 1000
+	defaultMaxBatchBytes = 1 << 20
+)
+
+func newSubscriber(id uint32, mailbox subMailbox, conf *subscriberConf) *subscriber {
+	ret := &subscriber{
+		id:      id,
+		name:    conf.name,
+		addr:    conf.addr,
+		mailbox: mailbox,
+		ack:     conf.ack,
+		policy:  client.OVERFLOW_DROP_OLDEST,
+
+		flush_interval: conf.flush_interval,
+	}
+	if ret.flush_interval <= 0 {
+		ret.flush_interval = flushInterval()
+	}
+	if ret.flush_interval < minFlushInterval {
+		ret.flush_interval = minFlushInterval
+	}
+	for _, policy := range []string{conf.policy, gConf.CacheLimit.Policy} {
+		if validOverflowPolicy(policy) {
+			ret.policy = policy
+			break
+		}
+	}
+	return ret
+}
+
+func validOverflowPolicy(policy string) bool {
+	switch policy {
+	case client.OVERFLOW_DROP_OLDEST, client.OVERFLOW_DROP_NEWEST, client.OVERFLOW_DISCONNECT, client.OVERFLOW_COALESCE:
+		return true
+	}
+	return false
+}
+
+func cacheLimit() (max_msgs, max_bytes int) {
+	max_msgs, max_bytes = gConf.CacheLimit.MaxMsgs, gConf.CacheLimit.MaxBytes
+	if max_msgs <= 0 {
+		max_msgs = defaultCacheMaxMsgs
+	}
+	if max_bytes <= 0 {
+		max_bytes = defaultCacheMaxBytes
+	}
+	return
+}
+
+func flushInterval() time.Duration {
+	if gConf.FlushInterval <= 0 {
+		return defaultFlushInterval
+	}
+	if gConf.FlushInterval < minFlushInterval {
+		return minFlushInterval
+	}
+	return gConf.FlushInterval
+}
+
+func batchLimit() (max_msgs, max_bytes int) {
+	max_msgs, max_bytes = gConf.MaxBatchMsgs, gConf.MaxBatchBytes
+	if max_msgs <= 0 {
+		max_msgs = defaultMaxBatchMsgs
+	}
+	if max_bytes <= 0 {
+		max_bytes = defaultMaxBatchBytes
+	}
+	return
+}
+
+func msgSize(msg *client.MsgSubscribeData) int {
+	return len(msg.Cmd) + len(msg.Data)
+}
+
+// push appends msgs to cache applying the overflow policy, it returns false if the subscriber
+// should be disconnected.
+func (s *subscriber) push(msgs []client.MsgSubscribeData) bool {
+	max_msgs, max_bytes := cacheLimit()
+	for _, msg := range msgs {
+		size := msgSize(&msg)
+		if len(s.cache)+1 <= max_msgs && s.cache_bytes+size <= max_bytes {
+			s.cache = append(s.cache, msg)
+			s.cache_bytes += size
+			continue
+		}
+
+		switch s.policy {
+		case client.OVERFLOW_DROP_NEWEST:
+			s.dropped++
+			continue
+		case client.OVERFLOW_DISCONNECT:
+			return false
+		case client.OVERFLOW_COALESCE:
+			s.coalesce()
+		}
+
+		s.cache = append(s.cache, msg)
+		s.cache_bytes += size
+		for len(s.cache) > 0 && (len(s.cache) > max_msgs || s.cache_bytes > max_bytes) {
+			s.cache_bytes -= msgSize(&s.cache[0])
+			s.cache = s.cache[1:]
+			s.dropped++
+		}
+	}
+	return true
+}
+
+// coalesce keeps only the latest data msg of each room and cmd in cache
+func (s *subscriber) coalesce() {
+	type cmdKey struct {
+		room_id int
+		cmd     string
+	}
+	latest := make(map[cmdKey]int)
+	for idx := range s.cache {
+		if s.cache[idx].MsgType == client.MSG_TYPE_DATA {
+			latest[cmdKey{room_id: s.cache[idx].RoomID, cmd: s.cache[idx].Cmd}] = idx
+		}
+	}
+
+	remain := make([]client.MsgSubscribeData, 0, len(latest))
+	s.cache_bytes = 0
+	for idx := range s.cache {
+		item := &s.cache[idx]
+		if item.MsgType == client.MSG_TYPE_DATA && latest[cmdKey{room_id: item.RoomID, cmd: item.Cmd}] != idx {
+			s.dropped++
+			continue
+		}
+		remain = append(remain, *item)
+		s.cache_bytes += msgSize(item)
+	}
+	s.cache = remain
+}
+
+// flush sends cached msgs to mailbox split by batch size limits, until all msgs are sent or
+// the mailbox is full.
+func (s *subscriber) flush() {
+	if s.mailbox == nil {
+		return // keep cached msgs for detached subscriber until it resumes
+	}
+	for {
+		batch, count := s.nextBatch()
+		if len(batch.Msgs) == 0 {
+			return
+		}
+
+		batch.Seq = s.seq + 1
+		select {
+		case s.mailbox <- batch:
+			s.commitBatch(&batch, count) // batch successfully send to mailbox
+		default:
+			return // keep msgs cached
+		}
+	}
+}
+
+// nextBatch returns leading cached msgs limited by batch size, with a MSG_TYPE_DROPPED msg
+// ahead if some msgs were dropped. Count is the number of cached msgs in the batch, they are
+// not removed from cache until commitBatch.
+func (s *subscriber) nextBatch() (batch client.MsgSubscribeBatch, count int) {
+	max_msgs, max_bytes := batchLimit()
+	if s.dropped > 0 {
+		batch.Msgs = append(batch.Msgs, client.MsgSubscribeData{
+			MsgType: client.MSG_TYPE_DROPPED,
+			Data:    []byte(`{"count":` + strconv.Itoa(s.dropped) + `}`),
+		})
+	}
+
+	size := 0
+	for count < len(s.cache) && len(batch.Msgs) < max_msgs {
+		item_size := msgSize(&s.cache[count])
+		if count > 0 && size+item_size > max_bytes {
+			break
+		}
+		batch.Msgs = append(batch.Msgs, s.cache[count])
+		size += item_size
+		count++
+	}
+	return
+}
+
+// commitBatch is called after batch taken by nextBatch is sent to mailbox
+func (s *subscriber) commitBatch(batch *client.MsgSubscribeBatch, count int) {
+	for _, item := range s.cache[:count] {
+		s.cache_bytes -= msgSize(&item)
+	}
+	s.cache = s.cache[count:]
+	if len(s.cache) == 0 {
+		s.cache = nil
+	}
+	s.dropped = 0
+	s.seq = batch.Seq
+	countMetric(&gCounters.batches_sent, 1)
+	if s.ack {
+		s.retain(batch)
+	}
+}
+
+// detachMailbox takes batches not yet received out of mailbox so that they could be
+// redelivered on resume.
+func (s *subscriber) detachMailbox() {
+	for s.mailbox != nil {
+		select {
+		case batch, ok := <-s.mailbox:
+			if !ok {
+				s.mailbox = nil
+			} else if !s.ack {
+				s.retain(&batch) // already retained in ack mode
+			}
+		default:
+			s.mailbox = nil
+		}
+	}
+}
+
+// retain keeps batch for redelivery, oldest batches are dropped if there are more than
+// maxUnackedBatches or their size exceeds the cache byte limit.
+func (s *subscriber) retain(batch *client.MsgSubscribeBatch) {
+	s.unacked = append(s.unacked, batch.Clone())
+	s.unacked_bytes += batchSize(batch)
+
+	_, max_bytes := cacheLimit()
+	idx := 0
+	for idx < len(s.unacked)-1 && (len(s.unacked)-idx > maxUnackedBatches || s.unacked_bytes > max_bytes) {
+		s.unacked_bytes -= batchSize(&s.unacked[idx])
+		idx++
+	}
+	if idx > 0 {
+		countMetric(&gCounters.dropped_batches, uint64(idx))
+		s.unacked = s.unacked[idx:]
+	}
+}
+
+func (s *subscriber) ackTo(seq uint64) {
+	idx := 0
+	for idx < len(s.unacked) && s.unacked[idx].Seq <= seq {
+		s.unacked_bytes -= batchSize(&s.unacked[idx])
+		idx++
+	}
+	s.unacked = s.unacked[idx:]
+}
+
+func batchSize(batch *client.MsgSubscribeBatch) int {
+	size := 0
+	for idx := range batch.Msgs {
+		size += msgSize(&batch.Msgs[idx])
+	}
+	return size
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zerozwt/brelay/client"
+)
+
+// testMsgs makes data msgs of room 1 from items like "A1", whose first letter is the cmd
+func testMsgs(items ...string) []client.MsgSubscribeData {
+	ret := make([]client.MsgSubscribeData, 0, len(items))
+	for _, item := range items {
+		ret = append(ret, client.MsgSubscribeData{RoomID: 1, MsgType: client.MSG_TYPE_DATA, Cmd: item[:1], Data: []byte(item)})
+	}
+	return ret
+}
+
+func msgDataList(msgs []client.MsgSubscribeData) string {
+	ret := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		ret = append(ret, string(msg.Data))
+	}
+	return strings.Join(ret, ",")
+}
+
+func TestSubscriberOverflow(t *testing.T) {
+	saved := gConf.CacheLimit
+	defer func() { gConf.CacheLimit = saved }()
+	gConf.CacheLimit = CacheLimitConfig{MaxMsgs: 4}
+
+	tests := []struct {
+		policy  string
+		ok      bool
+		cache   string
+		dropped int
+	}{
+		{client.OVERFLOW_DROP_OLDEST, true, "B2,A3,A4,A5", 1},
+		{client.OVERFLOW_DROP_NEWEST, true, "A1,B2,A3,A4", 1},
+		{client.OVERFLOW_DISCONNECT, false, "A1,B2,A3,A4", 0},
+		{client.OVERFLOW_COALESCE, true, "B2,A4,A5", 2},
+	}
+	for _, item := range tests {
+		sub := newSubscriber(1, newMailbox(), &subscriberConf{policy: item.policy})
+		if ok := sub.push(testMsgs("A1", "B2", "A3", "A4", "A5")); ok != item.ok {
+			t.Errorf("%s: push returns %v, expect %v", item.policy, ok, item.ok)
+			continue
+		}
+		if !item.ok {
+			continue // subscriber is disconnected, its cache does not matter
+		}
+		if got := msgDataList(sub.cache); got != item.cache || sub.dropped != item.dropped {
+			t.Errorf("%s: cache %s dropped %d, expect %s dropped %d", item.policy, got, sub.dropped, item.cache, item.dropped)
+		}
+		if sub.cache_bytes != 3*len(sub.cache) {
+			t.Errorf("%s: cache bytes %d mismatch with %d msgs", item.policy, sub.cache_bytes, len(sub.cache))
+		}
+
+		batch, count := sub.nextBatch()
+		if count != len(sub.cache) || len(batch.Msgs) != count+1 {
+			t.Errorf("%s: batch of %d msgs with %d cached, expect %d cached and a dropped report", item.policy, len(batch.Msgs), count, len(sub.cache))
+			continue
+		}
+		report := batch.Msgs[0]
+		if expect := `{"count":` + strconv.Itoa(item.dropped) + `}`; report.MsgType != client.MSG_TYPE_DROPPED || string(report.Data) != expect {
+			t.Errorf("%s: got report %+v, expect dropped msg with %s", item.policy, report, expect)
+		}
+		if got := msgDataList(batch.Msgs[1:]); got != item.cache {
+			t.Errorf("%s: batch %s, expect %s", item.policy, got, item.cache)
+		}
+
+		batch.Seq = 1
+		sub.commitBatch(&batch, count)
+		if sub.dropped != 0 || len(sub.cache) != 0 || sub.cache_bytes != 0 {
+			t.Errorf("%s: dropped %d cache %d bytes %d remain after commit", item.policy, sub.dropped, len(sub.cache), sub.cache_bytes)
+		}
+		if batch, _ = sub.nextBatch(); len(batch.Msgs) != 0 {
+			t.Errorf("%s: dropped report repeated after commit: %+v", item.policy, batch.Msgs)
+		}
+	}
+}
+
+func TestSubscriberCoalesceKeepsStates(t *testing.T) {
+	saved := gConf.CacheLimit
+	defer func() { gConf.CacheLimit = saved }()
+	gConf.CacheLimit = CacheLimitConfig{MaxMsgs: 3}
+
+	sub := newSubscriber(1, newMailbox(), &subscriberConf{policy: client.OVERFLOW_COALESCE})
+	state := client.MsgSubscribeData{RoomID: 1, MsgType: client.MSG_TYPE_WS_CONNECT, Data: []byte("S0")}
+	msgs := append([]client.MsgSubscribeData{state}, testMsgs("A1", "A2", "A3")...)
+	sub.push(msgs)
+	if got := msgDataList(sub.cache); got != "S0,A2,A3" || sub.dropped != 1 {
+		t.Errorf("cache %s dropped %d, expect S0,A2,A3 dropped 1", got, sub.dropped)
+	}
+}
+
+func TestSubscriberBatches(t *testing.T) {
+	saved_msgs, saved_bytes := gConf.MaxBatchMsgs, gConf.MaxBatchBytes
+	defer func() { gConf.MaxBatchMsgs, gConf.MaxBatchBytes = saved_msgs, saved_bytes }()
+
+	tests := []struct {
+		max_msgs  int
+		max_bytes int
+		msgs      []string
+		batches   []string
+	}{
+		{3, 0, []string{"A1", "A2", "A3", "A4", "A5", "A6", "A7"}, []string{"A1,A2,A3", "A4,A5,A6", "A7"}},
+		{0, 7, []string{"A1", "A2", "A3", "A4", "A5"}, []string{"A1,A2", "A3,A4", "A5"}},
+		{0, 7, []string{"A1", "A2345678", "A3"}, []string{"A1", "A2345678", "A3"}}, // oversized msg is sent alone
+		{2, 100, []string{"A1", "A2", "A3"}, []string{"A1,A2", "A3"}},
+	}
+	for idx, item := range tests {
+		gConf.MaxBatchMsgs, gConf.MaxBatchBytes = item.max_msgs, item.max_bytes
+		sub := newSubscriber(1, make(subMailbox, len(item.msgs)), &subscriberConf{})
+		sub.push(testMsgs(item.msgs...))
+		sub.flush()
+		if len(sub.cache) != 0 || sub.cache_bytes != 0 {
+			t.Errorf("case %d: %d msgs %d bytes remain cached", idx, len(sub.cache), sub.cache_bytes)
+		}
+		close(sub.mailbox)
+
+		got := []string{}
+		for batch := range sub.mailbox {
+			if batch.Seq != uint64(len(got)+1) {
+				t.Errorf("case %d: batch %d has seq %d", idx, len(got), batch.Seq)
+			}
+			got = append(got, msgDataList(batch.Msgs))
+		}
+		if strings.Join(got, "|") != strings.Join(item.batches, "|") {
+			t.Errorf("case %d: got batches %v, expect %v", idx, got, item.batches)
+		}
+	}
+}
+
+func TestSubscriberBatchesMailboxFull(t *testing.T) {
+	saved := gConf.MaxBatchMsgs
+	defer func() { gConf.MaxBatchMsgs = saved }()
+	gConf.MaxBatchMsgs = 2
+
+	sub := newSubscriber(1, make(subMailbox, 1), &subscriberConf{})
+	sub.push(testMsgs("A1", "A2", "A3"))
+	sub.flush()
+	if got := msgDataList(sub.cache); got != "A3" || sub.seq != 1 {
+		t.Fatalf("cache %s seq %d after mailbox full, expect A3 seq 1", got, sub.seq)
+	}
+	<-sub.mailbox
+	sub.flush()
+	if batch := <-sub.mailbox; msgDataList(batch.Msgs) != "A3" || batch.Seq != 2 {
+		t.Errorf("got batch %+v, expect A3 with seq 2", batch)
+	}
+}
+
+func TestFlushInterval(t *testing.T) {
+	saved := gConf.FlushInterval
+	defer func() { gConf.FlushInterval = saved }()
+	gConf.FlushInterval = time.Second
+
+	m := &dmManager{subscribers: make(map[uint32]*subscriber)}
+	fast := newSubscriber(1, newMailbox(), &subscriberConf{flush_interval: 100 * time.Millisecond})
+	slow := newSubscriber(2, newMailbox(), &subscriberConf{flush_interval: 300 * time.Millisecond})
+	dflt := newSubscriber(3, newMailbox(), &subscriberConf{})
+	m.subscribers[1], m.subscribers[2], m.subscribers[3] = fast, slow, dflt
+
+	now := time.Now()
+	tests := []struct {
+		at      time.Duration
+		flushed []*subscriber
+		next    time.Duration
+	}{
+		{0, []*subscriber{fast, slow, dflt}, 100 * time.Millisecond},
+		{50 * time.Millisecond, nil, 100 * time.Millisecond},
+		{100 * time.Millisecond, []*subscriber{fast}, 200 * time.Millisecond},
+		{200 * time.Millisecond, []*subscriber{fast}, 300 * time.Millisecond},
+		{300 * time.Millisecond, []*subscriber{fast, slow}, 400 * time.Millisecond},
+		{1000 * time.Millisecond, []*subscriber{fast, slow, dflt}, 1100 * time.Millisecond},
+	}
+	for idx, item := range tests {
+		for _, sub := range m.subscribers {
+			sub.push(testMsgs("A1"))
+		}
+		if next := m.flushData(now.Add(item.at)); !next.Equal(now.Add(item.next)) {
+			t.Errorf("case %d: next flush after %v, expect %v", idx, next.Sub(now), item.next)
+		}
+
+		flushed := make(map[*subscriber]bool)
+		for _, sub := range item.flushed {
+			flushed[sub] = true
+		}
+		for _, sub := range m.subscribers {
+			if sent := len(sub.mailbox) > 0; sent != flushed[sub] {
+				t.Errorf("case %d: subscriber %d flushed %v, expect %v", idx, sub.id, sent, flushed[sub])
+			}
+			if flushed[sub] && len(sub.cache) != 0 {
+				t.Errorf("case %d: subscriber %d keeps %d msgs after flush", idx, sub.id, len(sub.cache))
+			}
+			for len(sub.mailbox) > 0 {
+				<-sub.mailbox
+			}
+		}
+	}
+}
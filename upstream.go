@@ -0,0 +1,50 @@
+package main
+
+import (
+	dm "github.com/zerozwt/BLiveDanmaku"
+)
+
+// upstreamConn is a connection to the danmaku server of a live room
+type upstreamConn interface {
+	Room() *dm.RoomInfo
+	Close()
+}
+
+// upstreamHandler receives events of an upstream connection. OnMsg receives raw json of
+// every chat msg, OnLiveStateChange is called additionally for LIVE and PREPARING after room
+// info is refreshed. OnDisconnect is not called if the connection is closed by Close.
+type upstreamHandler struct {
+	OnMsg             func(conn upstreamConn, data []byte)
+	OnLiveStateChange func(conn upstreamConn, cmd string)
+	OnDisconnect      func(conn upstreamConn, err error)
+}
+
+// upstreamDialer connects to the danmaku server of a live room
+type upstreamDialer func(room_id int, handler *upstreamHandler) (upstreamConn, error)
+
+// dialBLive connects to bilibili live danmaku server
+func dialBLive(room_id int, handler *upstreamHandler) (upstreamConn, error) {
+	tmp_disconnect := func(dm_client *dm.Client, err error) {
+		handler.OnDisconnect(dm_client, err)
+	}
+	conf := &dm.ClientConf{
+		OnNetError:         tmp_disconnect,
+		OnServerDisconnect: tmp_disconnect,
+	}
+	conf.AddOpHandler(dm.OP_SEND_MSG_REPLY, func(dm_client *dm.Client, msg *dm.RawMessage) bool {
+		handler.OnMsg(dm_client, msg.Data)
+		return false
+	})
+	tmp_live_state_change := func(dm_client *dm.Client, cmd string, _ []byte) bool {
+		handler.OnLiveStateChange(dm_client, cmd)
+		return false
+	}
+	conf.AddCmdHandler(dm.CMD_LIVE, tmp_live_state_change)
+	conf.AddCmdHandler(dm.CMD_PREPARING, tmp_live_state_change)
+
+	ret, err := dm.Dial(room_id, conf)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/zerozwt/brelay/client"
+)
+
+// Webhook sinks POST each batch as json to a url:
+//
+//	{"seq":1,"msgs":[{"room":7777,"type":0,"cmd":"DANMU_MSG","data":{...}}]}
+//
+// with headers X-BRelay-Sink (sink name), X-BRelay-Timestamp (unix seconds) and, if secret
+// is set, X-BRelay-Signature: sha256=HEX(HMAC-SHA256(secret, timestamp + "." + body)).
+// Network errors, 429 and 5xx responses are retried with exponential backoff, the batch is
+// written to the dead-letter file after retries are exhausted or on other responses.
+
+const defaultWebhookTimeout = 10 * time.Second
+
+type webhookSink struct {
+	conf   WebhookConfig
+	client *http.Client
+}
+
+func initWebhooks() bool {
+	for idx := range gConf.Webhooks {
+		conf := gConf.Webhooks[idx]
+		if len(conf.URL) == 0 {
+			logger().Printf("url of webhook %s not specified", conf.Name)
+			return false
+		}
+		if conf.Timeout <= 0 {
+			conf.Timeout = defaultWebhookTimeout
+		}
+		conf.setDefaults()
+
+		sink := &webhookSink{conf: conf, client: &http.Client{Timeout: conf.Timeout}}
+		if !startSink("webhook "+conf.Name, conf.Rooms, conf.FlushInterval, sink.deliver) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *webhookSink) deliver(batch *client.MsgSubscribeBatch) {
+	body, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(newJSONBatch("", batch))
+	if err != nil {
+		writeDeadLetter(s.conf.DeadLetterFile, s.conf.Name, batch, err)
+		return
+	}
+
+	s.conf.SinkRetryConfig.deliver(s.conf.Name, batch, func() (bool, error) {
+		return s.post(batch.Seq, body)
+	})
+}
+
+// post sends body to url, returns whether the failure is retryable
+func (s *webhookSink) post(seq uint64, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.conf.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-BRelay-Sink", s.conf.Name)
+	req.Header.Set("X-BRelay-Seq", strconv.FormatUint(seq, 10))
+	req.Header.Set("X-BRelay-Timestamp", ts)
+	if len(s.conf.Secret) > 0 {
+		req.Header.Set("X-BRelay-Signature", "sha256="+webhookSignature(s.conf.Secret, ts, body))
+	}
+
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(ioutil.Discard, rsp.Body)
+	rsp.Body.Close()
+
+	if rsp.StatusCode >= 200 && rsp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("unexpected status %s", rsp.Status)
+	return rsp.StatusCode == http.StatusTooManyRequests || rsp.StatusCode >= 500, err
+}
+
+func webhookSignature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
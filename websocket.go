@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/zerozwt/brelay/client"
+)
+
+// WebSocket inbound serves browser clients with a JSON protocol. Each text frame sent by
+// client is a request:
+//
+//	{"method":"subscribe","req":1,"params":{"rooms":[{"room":7777,"cmds":["DANMU_MSG"]}]}}
+//
+// methods are login, resume, subscribe, subscribe_add, subscribe_remove, list_subscriptions,
+// ack and logout, whose params are json of the corresponding client.Msg*Req without
+// subscriber id and secret. Server replies with
+//
+//	{"type":"response","method":"subscribe","req":1,"ok":true,"msg":"","result":{...}}
+//
+// and streams batches after login or resume as
+//
+//	{"type":"batch","seq":1,"msgs":[{"room":7777,"type":0,"cmd":"DANMU_MSG","data":{...}}]}
+//
+// A connection serves at most one subscriber at a time, the subscriber is detached for
+// resume when the connection is broken.
+
+const wsWriteTimeout = 10 * time.Second
+const wsMaxRequestSize = 1 << 20
+const defaultWSPath = "/ws"
+
+type wsRequest struct {
+	Method string              `json:"method"`
+	Req    uint64              `json:"req"`
+	Params jsoniter.RawMessage `json:"params"`
+}
+
+type wsResponse struct {
+	Type   string      `json:"type"`
+	Method string      `json:"method"`
+	Req    uint64      `json:"req"`
+	Ok     bool        `json:"ok"`
+	Msg    string      `json:"msg"`
+	Result interface{} `json:"result,omitempty"`
+}
+
+// jsonBatch is json form of client.MsgSubscribeBatch with msg data kept as raw json, also
+// used by sinks
+type jsonBatch struct {
+	Type string        `json:"type,omitempty"`
+	Seq  uint64        `json:"seq"`
+	Msgs []jsonMsgData `json:"msgs"`
+}
+
+type jsonMsgData struct {
+	RoomID  int                 `json:"room"`
+	MsgType byte                `json:"type"`
+	Cmd     string              `json:"cmd"`
+	Data    jsoniter.RawMessage `json:"data"`
+	History bool                `json:"hist,omitempty"`
+}
+
+type wsSubscribeParams struct {
+	Rooms []client.MsgSubscribeRoom `json:"rooms"`
+}
+
+type wsAckParams struct {
+	Seq uint64 `json:"seq"`
+}
+
+// wsSession is state of a websocket connection, accessed by its serving goroutine only
+type wsSession struct {
+	conn *websocket.Conn
+	name string
+
+	sub_id      uint32
+	mb          subMailbox // nil before login
+	logging_out bool
+}
+
+func webSocketHandler(item *InboundConfig) http.Handler {
+	path := item.Path
+	if len(path) == 0 {
+		path = defaultWSPath
+	}
+
+	upgrader := &websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return checkOrigin(item.AllowOrigins, r)
+		},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			logger().Printf("websocket upgrade from %s failed: %v", r.RemoteAddr, err)
+			return
+		}
+		serveWebSocket(conn)
+	})
+	return mux
+}
+
+func checkOrigin(allow []string, r *http.Request) bool {
+	if len(allow) == 0 {
+		return true
+	}
+	origin := r.Header.Get("Origin")
+	for _, item := range allow {
+		if item == "*" || item == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func serveWebSocket(conn *websocket.Conn) {
+	wgAll.Add(1)
+	defer wgAll.Done()
+	defer conn.Close()
+
+	sess := &wsSession{conn: conn, name: conn.RemoteAddr().String()}
+	req_ch := make(chan *wsRequest)
+	done_ch := make(chan struct{})
+	defer close(done_ch)
+	go sess.readRequests(req_ch, done_ch)
+
+	for {
+		select {
+		case req, ok := <-req_ch:
+			if !ok {
+				logger().Printf("websocket connection of client %s closed", sess.name)
+				if sess.mb != nil {
+					gDanmaku.Detach(sess.sub_id, sess.mb, nil)
+				}
+				return
+			}
+			if err := sess.handle(req); err != nil {
+				return
+			}
+		case batch, ok := <-sess.mb:
+			if !ok {
+				if sess.logging_out {
+					sess.sub_id, sess.mb, sess.logging_out = 0, nil, false
+					continue
+				}
+				return // resumed by another connection or server shutdown
+			}
+			if err := sess.write(newJSONBatch("batch", &batch)); err != nil {
+				logger().Printf("send batch msgs to websocket client %s failed: %v", sess.name, err)
+				gDanmaku.Detach(sess.sub_id, sess.mb, &batch)
+				return
+			}
+		case <-gServer.CloseChannel():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutdown"), time.Now().Add(time.Second))
+			return
+		}
+	}
+}
+
+// readRequests reads requests until the connection is broken or done_ch is closed after the
+// session ended, malformed requests are skipped.
+func (s *wsSession) readRequests(req_ch chan *wsRequest, done_ch chan struct{}) {
+	defer close(req_ch)
+	s.conn.SetReadLimit(wsMaxRequestSize)
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		req := &wsRequest{}
+		if err := json.Unmarshal(data, req); err != nil {
+			logger().Printf("invalid websocket request from %s: %v", s.name, err)
+			continue
+		}
+		select {
+		case req_ch <- req:
+		case <-done_ch:
+			return
+		}
+	}
+}
+
+func (s *wsSession) write(obj interface{}) error {
+	data, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (s *wsSession) handle(req *wsRequest) error {
+	rsp := &wsResponse{Type: "response", Method: req.Method, Req: req.Req, Ok: true}
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	params := req.Params
+	if len(params) == 0 {
+		params = jsoniter.RawMessage("{}")
+	}
+
+	fail := func(msg string) {
+		rsp.Ok, rsp.Msg = false, msg
+	}
+
+	var redeliver []client.MsgSubscribeBatch
+	switch req.Method {
+	case "login":
+		login_req := client.MsgLoginReq{}
+		if err := json.Unmarshal(params, &login_req); err != nil {
+			fail(err.Error())
+			break
+		}
+		if s.mb != nil {
+			fail("already logged in")
+			break
+		}
+		mb := newMailbox()
+		sub_id, err := gDanmaku.AllocSubscriberID(mb, loginConf(&login_req, s.conn.RemoteAddr().String()))
+		if err != nil {
+			return err // it has to be a ErrInterrupted
+		}
+		logger().Printf("websocket client %s get an subscriber id %d", login_req.ID, sub_id)
+		s.name, s.sub_id, s.mb = login_req.ID, sub_id, mb
+		rsp.Result = &client.MsgLoginRsp{SubscriberID: sub_id, SubscriberSecret: getSecret(sub_id)}
+
+	case "resume":
+		resume_req := client.MsgResumeReq{}
+		if err := json.Unmarshal(params, &resume_req); err != nil {
+			fail(err.Error())
+			break
+		}
+		if s.mb != nil {
+			fail("already logged in")
+			break
+		}
+		if !bytes.Equal(getSecret(resume_req.SubscriberID), resume_req.SubscriberSecret) {
+			fail("secret check failed")
+			break
+		}
+		mb := newMailbox()
+		batches, ok, err := gDanmaku.Resume(resume_req.SubscriberID, mb, resume_req.LastSeq, resume_req.ID, s.conn.RemoteAddr().String())
+		if err != nil {
+			return err // it has to be a ErrInterrupted
+		}
+		if !ok {
+			fail("subscriber not found or expired")
+			break
+		}
+		logger().Printf("websocket client %s resumed subscriber %d, redeliver %d batches", resume_req.ID, resume_req.SubscriberID, len(batches))
+		s.name, s.sub_id, s.mb = resume_req.ID, resume_req.SubscriberID, mb
+		redeliver = batches
+
+	case "subscribe", "subscribe_add", "subscribe_remove":
+		sub_req := wsSubscribeParams{}
+		if err := json.Unmarshal(params, &sub_req); err != nil {
+			fail(err.Error())
+			break
+		}
+		if s.mb == nil {
+			fail("not logged in")
+			break
+		}
+		infos, err := compileSubscriptions(s.sub_id, sub_req.Rooms)
+		if err != nil {
+			fail(err.Error())
+			break
+		}
+		do := map[string]func(*client.MsgSubscribeReq, []*subscriberInfo){
+			"subscribe":        doSubscribe,
+			"subscribe_add":    doSubscribeAdd,
+			"subscribe_remove": doSubscribeRemove,
+		}[req.Method]
+		do(&client.MsgSubscribeReq{SubscriberID: s.sub_id, Rooms: sub_req.Rooms}, infos)
+
+	case "list_subscriptions":
+		if s.mb == nil {
+			fail("not logged in")
+			break
+		}
+		rooms, err := listSubscriptions(s.sub_id)
+		if err != nil {
+			return err // it has to be a ErrInterrupted
+		}
+		rsp.Result = &client.MsgListSubscriptionsRsp{Ok: true, Rooms: rooms}
+
+	case "ack":
+		ack := wsAckParams{}
+		if err := json.Unmarshal(params, &ack); err != nil {
+			fail(err.Error())
+			break
+		}
+		if s.mb != nil {
+			gDanmaku.Ack(s.sub_id, ack.Seq)
+		}
+		return nil // no response for acks
+
+	case "logout":
+		if s.mb == nil {
+			fail("not logged in")
+			break
+		}
+		gDanmaku.Logout(s.sub_id)
+		s.logging_out = true // remaining batches are sent until mailbox closed
+
+	default:
+		fail("unknown method")
+	}
+
+	if err := s.write(rsp); err != nil {
+		logger().Printf("send websocket %s response to %s failed: %v", req.Method, s.name, err)
+		if s.mb != nil && !s.logging_out {
+			gDanmaku.Detach(s.sub_id, s.mb, nil)
+		}
+		return err
+	}
+	for idx := range redeliver {
+		if err := s.write(newJSONBatch("batch", &redeliver[idx])); err != nil {
+			logger().Printf("redeliver batch msgs to websocket client %s failed: %v", s.name, err)
+			gDanmaku.Detach(s.sub_id, s.mb, nil)
+			return err
+		}
+	}
+	if len(redeliver) > 0 {
+		gDanmaku.Redelivered(s.sub_id, redeliver[len(redeliver)-1].Seq)
+	}
+	return nil
+}
+
+func newJSONBatch(batch_type string, batch *client.MsgSubscribeBatch) *jsonBatch {
+	ret := &jsonBatch{Type: batch_type, Seq: batch.Seq, Msgs: make([]jsonMsgData, 0, len(batch.Msgs))}
+	for _, msg := range batch.Msgs {
+		item := jsonMsgData{RoomID: msg.RoomID, MsgType: msg.MsgType, Cmd: msg.Cmd, History: msg.History}
+		if len(msg.Data) > 0 {
+			item.Data = msg.Data
+		}
+		ret.Msgs = append(ret.Msgs, item)
+	}
+	return ret
+}